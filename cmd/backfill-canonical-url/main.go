@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"deeliai/config"
+	"deeliai/internal/app"
+	"deeliai/internal/urlnorm"
+
+	"github.com/MatusOllah/slogcolor"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// backfill-canonical-url 是可選的工具，在 migration 000013 之後、000014 之前以 urlnorm 為既有文章補上 canonical_url
+// 沒有執行時 000014 以原始網址作為舊文章頁面的 key，只是之後收藏同一網址的不同寫法時不會對應到同一頁面
+// 同一使用者正規化後相同的文章只會補上其中一筆，其餘維持 NULL 並列出，由使用者自行決定是否刪除，本工具不會刪除任何資料
+//
+//	migrate -path migrations -database "$DATABASE_URL" goto 13
+//	go run ./cmd/backfill-canonical-url --dry-run   # 先確認會補上哪些文章
+//	go run ./cmd/backfill-canonical-url
+//	migrate -path migrations -database "$DATABASE_URL" up
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only report the changes without writing them")
+	flag.Parse()
+
+	slog.SetDefault(slog.New(slogcolor.NewHandler(os.Stderr, slogcolor.DefaultOptions)))
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+
+	db, err := app.ConnectDB(cfg)
+	if err != nil {
+		slog.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if err := backfill(context.Background(), db, *dryRun); err != nil {
+		slog.Error("Failed to backfill canonical_url", "error", err)
+		os.Exit(1)
+	}
+}
+
+// legacyArticle 是 000013 時 articles 資料表中判斷補上哪一筆所需的欄位
+type legacyArticle struct {
+	ID           uuid.UUID `db:"id"`
+	UserEmail    string    `db:"user_email"`
+	URL          string    `db:"url"`
+	CanonicalURL *string   `db:"canonical_url"`
+	Scraped      bool      `db:"scraped"`
+	CreatedAt    time.Time `db:"created_at"`
+
+	key string // 正規化後的網址
+}
+
+// backfill 在同一個 transaction 中為 canonical_url 為 NULL 的文章補上正規化網址
+// 同一使用者正規化後相同的文章受唯一索引限制只能補上一筆：已有 canonical_url 時不再補上，
+// 否則補上爬取成功、最早收藏的那一筆；其餘文章維持 NULL 並以 log 列出
+func backfill(ctx context.Context, db *sqlx.DB, dryRun bool) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 避免執行期間有新的文章寫入
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE articles IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+
+	var articles []legacyArticle
+	query := `
+		SELECT id, user_email, url, canonical_url, scrape_status = 'success' AS scraped, created_at
+		FROM articles
+	`
+	if err := tx.SelectContext(ctx, &articles, query); err != nil {
+		return err
+	}
+
+	groups := map[string][]*legacyArticle{}
+	var skipped int
+	for i := range articles {
+		a := &articles[i]
+		if a.CanonicalURL != nil {
+			a.key = *a.CanonicalURL
+		} else {
+			normalized, err := urlnorm.Normalize(a.URL)
+			if err != nil {
+				// 無法正規化的舊網址維持 NULL，000014 會以原始網址為 key
+				slog.Warn("Failed to normalize article url, leaving it as is", "article_id", a.ID, "url", a.URL)
+				skipped++
+				continue
+			}
+			a.key = normalized
+		}
+		groupKey := a.UserEmail + "\x00" + a.key
+		groups[groupKey] = append(groups[groupKey], a)
+	}
+
+	var filled, duplicates int
+	for _, group := range groups {
+		sort.Slice(group, func(i, j int) bool {
+			a, b := group[i], group[j]
+			if (a.CanonicalURL != nil) != (b.CanonicalURL != nil) {
+				return a.CanonicalURL != nil
+			}
+			if a.Scraped != b.Scraped {
+				return a.Scraped
+			}
+			return a.CreatedAt.Before(b.CreatedAt)
+		})
+
+		keep := group[0]
+		if len(group) > 1 {
+			ids := make([]uuid.UUID, 0, len(group)-1)
+			for _, d := range group[1:] {
+				ids = append(ids, d.ID)
+			}
+			slog.Warn("Found duplicate articles, leaving their canonical_url empty",
+				"user_email", keep.UserEmail, "canonical_url", keep.key, "kept", keep.ID, "duplicates", ids)
+			duplicates += len(ids)
+		}
+
+		if keep.CanonicalURL == nil {
+			if !dryRun {
+				if _, err := tx.ExecContext(ctx, `UPDATE articles SET canonical_url = $1 WHERE id = $2`, keep.key, keep.ID); err != nil {
+					return fmt.Errorf("update article %s: %w", keep.ID, err)
+				}
+			}
+			filled++
+		}
+	}
+
+	if dryRun {
+		slog.Info("Dry run finished, nothing was written", "articles_to_fill", filled, "duplicate_articles", duplicates, "invalid_urls", skipped)
+		return nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	slog.Info("Backfilled canonical_url", "articles_filled", filled, "duplicate_articles", duplicates, "invalid_urls", skipped)
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+
+	"deeliai/config"
+	"deeliai/internal/app"
+
+	"github.com/MatusOllah/slogcolor"
+)
+
+// worker 是獨立部署的爬取程序，預設只消化佇列任務，不提供 HTTP API
+func main() {
+	roleFlag := flag.String("role", string(app.RoleWorker), "process role: worker|scheduler")
+	flag.Parse()
+
+	// 初始化結構化日誌
+	slog.SetDefault(slog.New(slogcolor.NewHandler(os.Stderr, slogcolor.DefaultOptions)))
+
+	role, err := app.ParseRole(*roleFlag)
+	if err != nil || role == app.RoleAPI || role == app.RoleAll {
+		slog.Error("Invalid role for worker, expected worker|scheduler", "role", *roleFlag)
+		os.Exit(1)
+	}
+
+	// 載入設定
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Configuration loaded successfully")
+
+	if err := app.Run(cfg, role); err != nil {
+		slog.Error("Worker stopped with error", "error", err)
+		os.Exit(1)
+	}
+}
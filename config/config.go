@@ -2,16 +2,105 @@ package config
 
 import (
 	"strings"
+	"time"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	App struct {
-		Port      int    `yaml:"port"`
-		JWTSecret string `yaml:"jwt_secret"`
+		Port            int           `yaml:"port"`
+		JWTSecret       string        `yaml:"jwt_secret"`
+		AccessTokenTTL  time.Duration `yaml:"access_token_ttl"`
+		RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
 	} `yaml:"app"`
 
+	// JWT 未設定 keys 時，退回以 app.jwt_secret 簽發 HS256 token
+	JWT struct {
+		Issuer    string `yaml:"issuer"`
+		ActiveKID string `yaml:"active_kid"`
+		Keys      []struct {
+			KID            string `yaml:"kid"`
+			Algorithm      string `yaml:"algorithm"`
+			PrivateKeyFile string `yaml:"private_key_file"`
+			PublicKeyFile  string `yaml:"public_key_file"`
+		} `yaml:"keys"`
+	} `yaml:"jwt"`
+
+	// Queue 決定爬取任務佇列的實作：channel (單機記憶體)、postgres 或 redis (持久化，可多機共享)
+	Queue struct {
+		Driver            string        `yaml:"driver"`
+		WorkerCount       int           `yaml:"worker_count"`
+		BufferSize        int           `yaml:"buffer_size"`
+		PollInterval      time.Duration `yaml:"poll_interval"`
+		VisibilityTimeout time.Duration `yaml:"visibility_timeout"`
+		MaxDeliveries     int           `yaml:"max_deliveries"`
+
+		Redis struct {
+			Addr          string        `yaml:"addr"`
+			Password      string        `yaml:"password"`
+			DB            int           `yaml:"db"`
+			Stream        string        `yaml:"stream"`
+			Group         string        `yaml:"group"`
+			MaxLen        int64         `yaml:"max_len"`
+			Block         time.Duration `yaml:"block"`
+			ClaimMinIdle  time.Duration `yaml:"claim_min_idle"`
+			ClaimInterval time.Duration `yaml:"claim_interval"`
+		} `yaml:"redis"`
+	} `yaml:"queue"`
+
+	Scrape struct {
+		Retry struct {
+			MaxAttempts   int           `yaml:"max_attempts"`
+			BaseDelay     time.Duration `yaml:"base_delay"`
+			MaxDelay      time.Duration `yaml:"max_delay"`
+			CheckInterval time.Duration `yaml:"check_interval"`
+		} `yaml:"retry"`
+
+		Fetcher struct {
+			ConnectTimeout time.Duration `yaml:"connect_timeout"`
+			ReadTimeout    time.Duration `yaml:"read_timeout"`
+			MaxBodyBytes   int64         `yaml:"max_body_bytes"`
+			MaxRedirects   int           `yaml:"max_redirects"`
+			UserAgent      string        `yaml:"user_agent"`
+		} `yaml:"fetcher"`
+
+		// Guard 的項目可以是 IP、CIDR 或主機名稱；預設拒絕內網、loopback、link-local 與 metadata 位址
+		Guard struct {
+			Allow []string `yaml:"allow"`
+			Deny  []string `yaml:"deny"`
+		} `yaml:"guard"`
+
+		// Politeness 控制對單一主機的爬取頻率；RespectRobots 未設定時預設遵守 robots.txt
+		Politeness struct {
+			RespectRobots  *bool         `yaml:"respect_robots"`
+			MaxConcurrency int           `yaml:"max_concurrency_per_host"`
+			MinDelay       time.Duration `yaml:"min_delay"`
+			MaxWait        time.Duration `yaml:"max_wait"`
+			RobotsCacheTTL time.Duration `yaml:"robots_cache_ttl"`
+		} `yaml:"politeness"`
+
+		// Images 設定是否將預覽圖下載到本機並產生縮圖
+		Images struct {
+			Enabled   bool   `yaml:"enabled"`
+			Dir       string `yaml:"dir"`
+			MaxWidth  int    `yaml:"max_width"`
+			MaxHeight int    `yaml:"max_height"`
+		} `yaml:"images"`
+
+		// Refresh 控制共用頁面的重新爬取：爬取成功超過 TTL 的頁面由排程器或下一次收藏觸發重新爬取
+		Refresh struct {
+			TTL       time.Duration `yaml:"ttl"`
+			BatchSize int           `yaml:"batch_size"`
+		} `yaml:"refresh"`
+
+		Rescrape struct {
+			RatePerMinute float64 `yaml:"rate_per_minute"`
+			Burst         int     `yaml:"burst"`
+		} `yaml:"rescrape"`
+	} `yaml:"scrape"`
+
 	Database struct {
 		Driver   string `yaml:"driver"`
 		Host     string `yaml:"host"`
@@ -41,7 +130,10 @@ func LoadConfig() (*Config, error) {
 	var cfg Config
 
 	// 將設定檔內容解析到 Config 結構體
-	if err := viper.Unmarshal(&cfg); err != nil {
+	// 使用 yaml tag 對應設定鍵值，否則像 jwt_secret 這類含底線的鍵會對不到欄位
+	if err := viper.Unmarshal(&cfg, func(dc *mapstructure.DecoderConfig) {
+		dc.TagName = "yaml"
+	}); err != nil {
 		return nil, err
 	}
 
@@ -15,6 +15,26 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "以 JWKS (RFC 7517) 格式公開目前有效的驗證公鑰，供其他服務驗證 token",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "取得 JWT 驗證公鑰",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.JWKSet"
+                        }
+                    }
+                }
+            }
+        },
         "/articles": {
             "get": {
                 "security": [
@@ -22,7 +42,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "獲取使用者收藏的文章列表",
+                "description": "以 keyset 分頁取得使用者收藏的文章列表：第一頁不帶 cursor，之後帶上前一頁回傳的 next_cursor，換頁期間新增的文章不會造成重複或遺漏\ncursor 綁定排序方式，更換 sort/order 時需從第一頁重新開始",
                 "produces": [
                     "application/json"
                 ],
@@ -42,15 +62,109 @@ const docTemplate = `{
                     {
                         "type": "integer",
                         "default": 10,
-                        "description": "限制返回數量",
+                        "description": "每頁數量 (1-100)",
                         "name": "limit",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "前一頁回傳的 next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "rating",
+                            "title"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "排序欄位",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "排序方向，預設日期與評分為 desc、標題為 asc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "success",
+                            "failed",
+                            "failed_permanent",
+                            "deferred"
+                        ],
+                        "type": "string",
+                        "description": "爬取狀態",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "網域，包含子網域",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "標籤名稱 (不分大小寫)",
+                        "name": "tag",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "default": 0,
-                        "description": "跳過數量",
-                        "name": "offset",
+                        "description": "評分下限 (1-5)",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "評分上限 (1-5)",
+                        "name": "max_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "收藏時間下限 (RFC 3339)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "收藏時間上限 (RFC 3339，不含)",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否已讀",
+                        "name": "read",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "false",
+                            "true",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "false",
+                        "description": "是否已封存，預設只列出未封存的文章",
+                        "name": "archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否加入最愛",
+                        "name": "favorited",
                         "in": "query"
                     }
                 ],
@@ -66,16 +180,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/model.Article"
-                                            }
+                                            "$ref": "#/definitions/handler.ArticleListResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "無效的查詢參數或 cursor",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "未授權",
                         "schema": {
@@ -96,7 +213,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "提交一個文章 URL，後台會自動爬取 metadata",
+                "description": "提交一個文章 URL，後台會自動爬取 metadata；網址會先正規化 (忽略 utm_* 等追蹤參數、結尾斜線、http/https 與 www./m. 子網域)，重複收藏時回傳既有文章",
                 "consumes": [
                     "application/json"
                 ],
@@ -127,7 +244,25 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "202": {
+                    "200": {
+                        "description": "已收藏過相同網址，回傳既有文章 (duplicate 為 true)",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.PostArticleResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "201": {
                         "description": "文章正在處理中",
                         "schema": {
                             "allOf": [
@@ -138,7 +273,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.Article"
+                                            "$ref": "#/definitions/handler.PostArticleResponse"
                                         }
                                     }
                                 }
@@ -166,18 +301,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/articles/{id}": {
-            "delete": {
+        "/articles/rescrape": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "刪除使用者收藏的指定文章",
+                "description": "依爬取狀態、網域與最後更新時間篩選文章，將所屬頁面重新排入佇列，單次最多 500 個頁面",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "articles"
                 ],
-                "summary": "刪除文章",
+                "summary": "批次重新爬取文章",
                 "parameters": [
                     {
                         "type": "string",
@@ -188,19 +329,41 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "文章 ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "篩選條件",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.BulkRescrapeRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "文章成功刪除"
+                    "202": {
+                        "description": "文章已重新排入爬取佇列",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "properties": {
+                                                "queued": {
+                                                    "type": "integer"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
                     "400": {
-                        "description": "無效的文章 ID",
+                        "description": "無效的請求",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -211,8 +374,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "文章不存在",
+                    "429": {
+                        "description": "請求過於頻繁",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -226,21 +389,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/articles/{id}/rate": {
+        "/articles/search": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "獲取使用者對指定文章的評分與標籤",
+                "description": "以全文搜尋使用者收藏的文章 (標題、描述、網址、筆記與標籤)，依相關度排序；支援 websearch 語法 (例如 \"片語\"、-排除、or)\n中文等沒有空白分詞的內容會以部分字串比對標題與描述；snippet 中符合的字詞以 \u003cmark\u003e\u003c/mark\u003e 標示",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "ratings"
+                    "articles"
                 ],
-                "summary": "獲取使用者對文章的評分",
+                "summary": "搜尋文章",
                 "parameters": [
                     {
                         "type": "string",
@@ -252,15 +415,29 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "文章 ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "搜尋關鍵字",
+                        "name": "q",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "限制返回數量 (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "跳過數量",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "成功獲取評分",
+                        "description": "搜尋結果",
                         "schema": {
                             "allOf": [
                                 {
@@ -270,7 +447,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.Rating"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.ArticleSearchResult"
+                                            }
                                         }
                                     }
                                 }
@@ -278,7 +458,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "無效的文章 ID",
+                        "description": "無效的查詢參數",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -289,12 +469,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "找不到評分",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "內部伺服器錯誤",
                         "schema": {
@@ -302,14 +476,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/articles/state": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "為指定文章評分並新增標籤",
+                "description": "一次修改多篇文章 (最多 500 篇) 的已讀、封存與最愛狀態，只會修改有帶上的欄位，不屬於使用者的文章會被略過",
                 "consumes": [
                     "application/json"
                 ],
@@ -317,9 +493,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ratings"
+                    "articles"
                 ],
-                "summary": "評分並標記文章",
+                "summary": "批次修改文章的閱讀狀態",
                 "parameters": [
                     {
                         "type": "string",
@@ -330,25 +506,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "文章 ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "評分與標籤",
+                        "description": "文章 ID 與要修改的狀態",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.RateArticleRequest"
+                            "$ref": "#/definitions/handler.BulkArticleStateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "評分成功",
+                        "description": "實際修改的文章數",
                         "schema": {
                             "allOf": [
                                 {
@@ -358,7 +527,12 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.Rating"
+                                            "type": "object",
+                                            "properties": {
+                                                "updated": {
+                                                    "type": "integer"
+                                                }
+                                            }
                                         }
                                     }
                                 }
@@ -366,7 +540,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "無效的請求或評分值",
+                        "description": "無效的請求",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -384,18 +558,20 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/articles/{id}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "刪除使用者對指定文章的評分與標籤",
+                "description": "刪除使用者收藏的指定文章",
                 "tags": [
-                    "ratings"
+                    "articles"
                 ],
-                "summary": "刪除評分",
+                "summary": "刪除文章",
                 "parameters": [
                     {
                         "type": "string",
@@ -415,7 +591,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "評分成功刪除"
+                        "description": "文章成功刪除"
                     },
                     "400": {
                         "description": "無效的文章 ID",
@@ -430,7 +606,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "找不到評分",
+                        "description": "文章不存在",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -442,11 +618,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/login": {
-            "post": {
-                "description": "使用者憑 E-mail 和密碼登入",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "修改使用者自訂的標題、描述與筆記，自訂值優先於爬取到的值且重新爬取時不會被覆蓋；只會修改有帶上的欄位，空字串代表清除自訂值",
                 "consumes": [
                     "application/json"
                 ],
@@ -454,23 +633,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "articles"
                 ],
-                "summary": "使用者登入",
+                "summary": "修改文章",
                 "parameters": [
                     {
-                        "description": "登入請求",
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "要修改的欄位",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.LoginRequest"
+                            "$ref": "#/definitions/handler.PatchArticleRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "文章修改成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -480,12 +674,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object",
-                                            "properties": {
-                                                "token": {
-                                                    "type": "string"
-                                                }
-                                            }
+                                            "$ref": "#/definitions/model.Article"
                                         }
                                     }
                                 }
@@ -493,13 +682,19 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "無效的請求",
+                        "description": "無效的請求或文章 ID",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "憑證無效",
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "文章不存在",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -513,21 +708,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/me": {
+        "/articles/{id}/content": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "透過 JWT 驗證獲取使用者個人資料",
+                "description": "取得爬取時擷取出的文章主體 (已 sanitize 的 HTML 與純文字)，以及字數與預估閱讀時間",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "articles"
                 ],
-                "summary": "獲取使用者個人資料",
+                "summary": "獲取文章內文",
                 "parameters": [
                     {
                         "type": "string",
@@ -536,11 +731,18 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "成功獲取使用者資料",
+                        "description": "成功獲取文章內文",
                         "schema": {
                             "allOf": [
                                 {
@@ -550,15 +752,27 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.User"
+                                            "$ref": "#/definitions/model.PageContent"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "無效的文章 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "401": {
-                        "description": "未授權，JWT 驗證失敗",
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "文章不存在或尚未擷取內文",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -572,21 +786,87 @@ const docTemplate = `{
                 }
             }
         },
-        "/recommendations": {
+        "/articles/{id}/image": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "根據使用者的歷史評分與標籤，推薦相關的新文章",
+                "description": "回傳快取的預覽圖縮圖 (JPEG)；尚未快取時回傳 404，前端可改用文章的 image_url",
+                "produces": [
+                    "image/jpeg"
+                ],
+                "tags": [
+                    "articles"
+                ],
+                "summary": "獲取文章預覽圖",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "縮圖",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "無效的文章 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "文章不存在或尚未快取預覽圖",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/articles/{id}/rate": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "獲取使用者對指定文章的評分與標籤",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "recommendations"
+                    "ratings"
                 ],
-                "summary": "獲取文章推薦列表",
+                "summary": "獲取使用者對文章的評分",
                 "parameters": [
                     {
                         "type": "string",
@@ -595,11 +875,18 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "成功獲取推薦文章列表",
+                        "description": "成功獲取評分",
                         "schema": {
                             "allOf": [
                                 {
@@ -609,18 +896,27 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/model.Article"
-                                            }
+                                            "$ref": "#/definitions/model.Rating"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "無效的文章 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "401": {
-                        "description": "未授權，JWT 驗證失敗",
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "找不到評分",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -632,11 +928,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/signup": {
+            },
             "post": {
-                "description": "使用者註冊一個新帳號",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "為指定文章評分；帶上 tags 時同時將文章的標籤替換為 tags，省略時不修改標籤",
                 "consumes": [
                     "application/json"
                 ],
@@ -644,23 +943,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "ratings"
                 ],
-                "summary": "註冊新使用者",
+                "summary": "評分並標記文章",
                 "parameters": [
                     {
-                        "description": "註冊請求",
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "評分與標籤",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.SignupRequest"
+                            "$ref": "#/definitions/handler.RateArticleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "評分成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -670,7 +984,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/model.User"
+                                            "$ref": "#/definitions/model.Rating"
                                         }
                                     }
                                 }
@@ -678,7 +992,13 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "無效的請求",
+                        "description": "無效的請求或評分值",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -690,111 +1010,2695 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "handler.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
-                },
-                "message": {
-                    "type": "string"
-                }
-            }
-        },
-        "handler.LoginRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "刪除使用者對指定文章的評分與標籤",
+                "tags": [
+                    "ratings"
+                ],
+                "summary": "刪除評分",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "評分成功刪除"
+                    },
+                    "400": {
+                        "description": "無效的文章 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "找不到評分",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "handler.PostArticleRequest": {
-            "type": "object",
-            "required": [
-                "url"
-            ],
-            "properties": {
-                "url": {
+        "/articles/{id}/rescrape": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "重設文章的爬取狀態與重試次數並重新排入佇列，可用來更新已變動的 metadata",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "articles"
+                ],
+                "summary": "重新爬取文章",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "文章已重新排入爬取佇列",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Article"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "無效的文章 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "文章不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "請求過於頻繁",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/articles/{id}/scrape-attempts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "列出文章最近的爬取嘗試 (新的在前)，包含 HTTP 狀態碼、錯誤分類、錯誤訊息、耗時與最終 URL",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "articles"
+                ],
+                "summary": "獲取文章的爬取紀錄",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功獲取爬取紀錄",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.ScrapeAttempt"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "無效的文章 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "文章不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/articles/{id}/state": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "標記文章為已讀/未讀、封存/取消封存、加入/移除最愛，只會修改有帶上的欄位；重複標記為已讀或封存時保留第一次的時間",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "articles"
+                ],
+                "summary": "修改文章的閱讀狀態",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "要修改的狀態",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ArticleStateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Article"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "無效的請求",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "文章不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/articles/{id}/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "列出文章的標籤，依名稱排序",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "獲取文章的標籤",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功獲取標籤",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Tag"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "無效的文章 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "文章不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "為文章加上標籤，不需要先評分；尚不存在的標籤會自動建立，名稱會去除前後空白、合併連續空白並轉為小寫",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "為文章加上標籤",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "標籤名稱",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AddArticleTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "文章加上後的所有標籤",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Tag"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "無效的文章 ID 或標籤名稱",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "文章不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/articles/{id}/tags/{tag_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "從文章移除標籤，標籤本身保留",
+                "tags": [
+                    "tags"
+                ],
+                "summary": "移除文章的標籤",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "標籤 ID",
+                        "name": "tag_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "移除成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "無效的文章或標籤 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "文章不存在或沒有此標籤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "以 refresh token 換發新的 access token，舊的 refresh token 會立即失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "刷新 Token",
+                "parameters": [
+                    {
+                        "description": "刷新請求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.TokenPair"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "無效的請求",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "refresh token 無效、過期或已被撤銷",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "列出使用者所有收藏夾與各自的文章數，預設的 Inbox 排在最前面",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "獲取收藏夾列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功獲取收藏夾列表",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Collection"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "建立新的收藏夾，名稱不可與使用者其他收藏夾重複",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "建立收藏夾",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "收藏夾名稱與描述",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateCollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "建立成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Collection"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "無效的請求",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "已有同名的收藏夾",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "取得單一收藏夾的資訊與文章數",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "獲取收藏夾",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "收藏夾 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功獲取收藏夾",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Collection"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "無效的收藏夾 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "收藏夾不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "刪除收藏夾，其中的文章仍保留在使用者的收藏中；預設的 Inbox 不能刪除",
+                "tags": [
+                    "collections"
+                ],
+                "summary": "刪除收藏夾",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "收藏夾 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "刪除成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "無效的收藏夾 ID 或為預設收藏夾",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "收藏夾不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "修改收藏夾的名稱或描述，只會修改有帶上的欄位；description 傳入空字串代表清除",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "修改收藏夾",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "收藏夾 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "要修改的欄位",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PatchCollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Collection"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "無效的請求",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "收藏夾不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "已有同名的收藏夾",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/articles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "以與文章列表相同的 keyset 分頁與篩選條件取得收藏夾中的文章，預設依收藏夾中的順序 (sort=position) 由前到後排列",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "獲取收藏夾中的文章",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "收藏夾 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "每頁數量 (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "前一頁回傳的 next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "position",
+                            "created_at",
+                            "rating",
+                            "title"
+                        ],
+                        "type": "string",
+                        "default": "position",
+                        "description": "排序欄位",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "排序方向，預設日期與評分為 desc、標題與順序為 asc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "success",
+                            "failed",
+                            "failed_permanent",
+                            "deferred"
+                        ],
+                        "type": "string",
+                        "description": "爬取狀態",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "網域，包含子網域",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "標籤名稱 (不分大小寫)",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "評分下限 (1-5)",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "評分上限 (1-5)",
+                        "name": "max_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "收藏時間下限 (RFC 3339)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "收藏時間上限 (RFC 3339，不含)",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否已讀",
+                        "name": "read",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "false",
+                            "true",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "false",
+                        "description": "是否已封存，預設只列出未封存的文章",
+                        "name": "archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否加入最愛",
+                        "name": "favorited",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功獲取文章列表",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.ArticleListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "無效的查詢參數或 cursor",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "收藏夾不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "將使用者的文章加到收藏夾的最後面，文章已在收藏夾中時不做任何事；一篇文章可以放在多個收藏夾中",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "將文章加到收藏夾",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "收藏夾 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "文章 ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AddCollectionArticleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "加入成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "無效的請求",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "收藏夾或文章不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/articles/{article_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "將文章移出收藏夾，文章本身仍保留在使用者的收藏中",
+                "tags": [
+                    "collections"
+                ],
+                "summary": "將文章移出收藏夾",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "收藏夾 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "article_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "移除成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "無效的收藏夾或文章 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "收藏夾不存在或文章不在收藏夾中",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "將文章移到收藏夾中的第 position 個 (從 1 開始，超過文章數時移到最後)，其餘文章維持原本的相對順序",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "調整文章在收藏夾中的順序",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "收藏夾 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文章 ID",
+                        "name": "article_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新的位置",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MoveCollectionArticleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "無效的請求",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "收藏夾不存在或文章不在收藏夾中",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "使用者憑 E-mail 和密碼登入",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "使用者登入",
+                "parameters": [
+                    {
+                        "description": "登入請求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.TokenPair"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "無效的請求",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "憑證無效",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "撤銷目前裝置的 Session，對應的 access token 與 refresh token 皆會失效",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "登出",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "登出成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.StandardResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權，JWT 驗證失敗",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/logout-all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "撤銷使用者所有的 Session",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "登出所有裝置",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "登出成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.StandardResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權，JWT 驗證失敗",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "透過 JWT 驗證獲取使用者個人資料",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "獲取使用者個人資料",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功獲取使用者資料",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授權，JWT 驗證失敗",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根據使用者的歷史評分與標籤，推薦相關的新文章",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "獲取文章推薦列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功獲取推薦頁面列表 (使用者尚未收藏的網址)",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Page"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授權，JWT 驗證失敗",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/signup": {
+            "post": {
+                "description": "使用者註冊一個新帳號",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "註冊新使用者",
+                "parameters": [
+                    {
+                        "description": "註冊請求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SignupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "無效的請求",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "列出使用者所有標籤與各自的文章數，依文章數由多到少排序",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "獲取標籤列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功獲取標籤列表",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.TagCount"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "刪除標籤並從所有文章移除",
+                "tags": [
+                    "tags"
+                ],
+                "summary": "刪除標籤",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "標籤 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "刪除成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "無效的標籤 ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "標籤不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "修改標籤名稱，所有使用該標籤的文章都會跟著改變；名稱會去除前後空白、合併連續空白並轉為小寫，與既有標籤相同時兩者合併",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "重新命名標籤",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cyour_JWT_token\u003e",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "標籤 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新的標籤名稱",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RenameTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Tag"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "無效的標籤 ID 或名稱",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未授權",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "標籤不存在",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "內部伺服器錯誤",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.AddArticleTagsRequest": {
+            "type": "object",
+            "required": [
+                "tags"
+            ],
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.AddCollectionArticleRequest": {
+            "type": "object",
+            "required": [
+                "article_id"
+            ],
+            "properties": {
+                "article_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ArticleListResponse": {
+            "type": "object",
+            "properties": {
+                "articles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Article"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.ArticleStateRequest": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "favorited": {
+                    "type": "boolean"
+                },
+                "read": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.BulkArticleStateRequest": {
+            "type": "object",
+            "required": [
+                "article_ids"
+            ],
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "article_ids": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "favorited": {
+                    "type": "boolean"
+                },
+                "read": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.BulkRescrapeRequest": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "older_than": {
+                    "description": "RFC 3339，只重新爬取最後更新時間早於此時間的文章",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "待爬取的頁面已在佇列中，不會重新排入",
+                    "type": "string",
+                    "enum": [
+                        "success",
+                        "failed",
+                        "failed_permanent",
+                        "deferred"
+                    ]
+                }
+            }
+        },
+        "handler.CreateCollectionRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "handler.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "handler.MoveCollectionArticleRequest": {
+            "type": "object",
+            "required": [
+                "position"
+            ],
+            "properties": {
+                "position": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "handler.PatchArticleRequest": {
+            "type": "object",
+            "properties": {
+                "custom_description": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "custom_title": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "notes": {
+                    "type": "string",
+                    "maxLength": 10000
+                }
+            }
+        },
+        "handler.PatchCollectionRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "handler.PostArticleRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.PostArticleResponse": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "description": "封存的時間，未封存為 nil",
+                    "type": "string"
+                },
+                "author": {
+                    "type": "string"
+                },
+                "canonical_link": {
+                    "description": "頁面宣告的 canonical 網址",
+                    "type": "string"
+                },
+                "canonical_url": {
+                    "description": "正規化後的網址，用於偵測重複收藏",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "custom_description": {
+                    "type": "string"
+                },
+                "custom_title": {
+                    "type": "string"
+                },
+                "description": {
+                    "description": "有 custom_description 時為 custom_description，否則為爬取到的描述",
+                    "type": "string"
+                },
+                "document_type": {
+                    "description": "html、pdf、image 或 text",
+                    "type": "string"
+                },
+                "duplicate": {
+                    "type": "boolean"
+                },
+                "extras": {
+                    "description": "網站特有的資訊，例如影片長度、repo 星數",
+                    "type": "object"
+                },
+                "favicon_url": {
+                    "type": "string"
+                },
+                "favorited": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "language": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "description": "最近一次爬取失敗的原因",
+                    "type": "string"
+                },
+                "modified_at": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "oembed_url": {
+                    "description": "oEmbed 端點，供前端嵌入內容",
+                    "type": "string"
+                },
+                "page_id": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "read_at": {
+                    "description": "第一次標記為已讀的時間，未讀為 nil",
+                    "type": "string"
+                },
+                "scrape_status": {
+                    "type": "string"
+                },
+                "site_name": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "使用者加上的標籤，依名稱排序",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "有 custom_title 時為 custom_title，否則為爬取到的標題",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "文章或頁面最後更新的時間",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "使用者提交的原始網址",
+                    "type": "string"
+                },
+                "user_email": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.RateArticleRequest": {
+            "type": "object",
+            "required": [
+                "scores"
+            ],
+            "properties": {
+                "scores": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.RenameTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.SignupRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "handler.StandardResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Article": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "description": "封存的時間，未封存為 nil",
+                    "type": "string"
+                },
+                "author": {
+                    "type": "string"
+                },
+                "canonical_link": {
+                    "description": "頁面宣告的 canonical 網址",
+                    "type": "string"
+                },
+                "canonical_url": {
+                    "description": "正規化後的網址，用於偵測重複收藏",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "custom_description": {
+                    "type": "string"
+                },
+                "custom_title": {
+                    "type": "string"
+                },
+                "description": {
+                    "description": "有 custom_description 時為 custom_description，否則為爬取到的描述",
+                    "type": "string"
+                },
+                "document_type": {
+                    "description": "html、pdf、image 或 text",
+                    "type": "string"
+                },
+                "extras": {
+                    "description": "網站特有的資訊，例如影片長度、repo 星數",
+                    "type": "object"
+                },
+                "favicon_url": {
+                    "type": "string"
+                },
+                "favorited": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "language": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "description": "最近一次爬取失敗的原因",
+                    "type": "string"
+                },
+                "modified_at": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "oembed_url": {
+                    "description": "oEmbed 端點，供前端嵌入內容",
+                    "type": "string"
+                },
+                "page_id": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "read_at": {
+                    "description": "第一次標記為已讀的時間，未讀為 nil",
+                    "type": "string"
+                },
+                "scrape_status": {
+                    "type": "string"
+                },
+                "site_name": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "使用者加上的標籤，依名稱排序",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "有 custom_title 時為 custom_title，否則為爬取到的標題",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "文章或頁面最後更新的時間",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "使用者提交的原始網址",
+                    "type": "string"
+                },
+                "user_email": {
                     "type": "string"
                 }
             }
         },
-        "handler.RateArticleRequest": {
+        "model.ArticleSearchResult": {
             "type": "object",
-            "required": [
-                "scores",
-                "tags"
-            ],
             "properties": {
-                "scores": {
-                    "type": "integer",
-                    "maximum": 5,
-                    "minimum": 1
+                "archived_at": {
+                    "description": "封存的時間，未封存為 nil",
+                    "type": "string"
+                },
+                "author": {
+                    "type": "string"
+                },
+                "canonical_link": {
+                    "description": "頁面宣告的 canonical 網址",
+                    "type": "string"
+                },
+                "canonical_url": {
+                    "description": "正規化後的網址，用於偵測重複收藏",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "custom_description": {
+                    "type": "string"
+                },
+                "custom_title": {
+                    "type": "string"
+                },
+                "description": {
+                    "description": "有 custom_description 時為 custom_description，否則為爬取到的描述",
+                    "type": "string"
+                },
+                "document_type": {
+                    "description": "html、pdf、image 或 text",
+                    "type": "string"
+                },
+                "extras": {
+                    "description": "網站特有的資訊，例如影片長度、repo 星數",
+                    "type": "object"
+                },
+                "favicon_url": {
+                    "type": "string"
+                },
+                "favorited": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "language": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "description": "最近一次爬取失敗的原因",
+                    "type": "string"
+                },
+                "modified_at": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "oembed_url": {
+                    "description": "oEmbed 端點，供前端嵌入內容",
+                    "type": "string"
+                },
+                "page_id": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "read_at": {
+                    "description": "第一次標記為已讀的時間，未讀為 nil",
+                    "type": "string"
+                },
+                "scrape_status": {
+                    "type": "string"
+                },
+                "site_name": {
+                    "type": "string"
+                },
+                "snippet": {
+                    "type": "string"
                 },
                 "tags": {
+                    "description": "使用者加上的標籤，依名稱排序",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
-                }
-            }
-        },
-        "handler.SignupRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
+                },
+                "title": {
+                    "description": "有 custom_title 時為 custom_title，否則為爬取到的標題",
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
+                "updated_at": {
+                    "description": "文章或頁面最後更新的時間",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "使用者提交的原始網址",
+                    "type": "string"
+                },
+                "user_email": {
+                    "type": "string"
                 }
             }
         },
-        "handler.StandardResponse": {
+        "model.Collection": {
             "type": "object",
             "properties": {
-                "data": {},
-                "message": {
+                "article_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_email": {
                     "type": "string"
                 }
             }
         },
-        "model.Article": {
+        "model.Page": {
             "type": "object",
             "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "canonical_link": {
+                    "type": "string"
+                },
+                "canonical_url": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
                 "description": {
                     "type": "string"
                 },
+                "document_type": {
+                    "type": "string"
+                },
+                "extras": {
+                    "type": "object"
+                },
+                "favicon_url": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
                 "image_url": {
                     "type": "string"
                 },
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "language": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "modified_at": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "oembed_url": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
                 "scrape_status": {
                     "type": "string"
                 },
+                "scraped_at": {
+                    "description": "最近一次爬取成功的時間",
+                    "type": "string"
+                },
+                "site_name": {
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
                 },
@@ -802,10 +3706,31 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "url": {
+                    "description": "實際爬取的網址，取第一位使用者提交的原始網址",
+                    "type": "string"
+                }
+            }
+        },
+        "model.PageContent": {
+            "type": "object",
+            "properties": {
+                "content_html": {
                     "type": "string"
                 },
-                "user_email": {
+                "content_text": {
+                    "type": "string"
+                },
+                "extracted_at": {
                     "type": "string"
+                },
+                "page_id": {
+                    "type": "string"
+                },
+                "reading_time_minutes": {
+                    "type": "integer"
+                },
+                "word_count": {
+                    "type": "integer"
                 }
             }
         },
@@ -825,6 +3750,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "tags": {
+                    "description": "文章的標籤，存在 article_tags，與評分分開管理",
                     "type": "array",
                     "items": {
                         "type": "string"
@@ -838,6 +3764,91 @@ const docTemplate = `{
                 }
             }
         },
+        "model.ScrapeAttempt": {
+            "type": "object",
+            "properties": {
+                "attempted_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error_class": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "final_url": {
+                    "description": "跟隨轉址後實際抓取的 URL",
+                    "type": "string"
+                },
+                "http_status": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "page_id": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.Tag": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_email": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TagCount": {
+            "type": "object",
+            "properties": {
+                "article_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_email": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TokenPair": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "description": "access token 剩餘秒數",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "model.User": {
             "type": "object",
             "properties": {
@@ -851,6 +3862,49 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "service.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                },
+                "y": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.JWKSet": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.JWK"
+                    }
+                }
+            }
         }
     }
 }`
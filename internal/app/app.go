@@ -0,0 +1,226 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"deeliai/config"
+	"deeliai/internal/handler"
+	"deeliai/internal/interfaces"
+	"deeliai/internal/middleware"
+	"deeliai/internal/repository/sqlximpl"
+	"deeliai/internal/scraper"
+	"deeliai/internal/scraper/extractors"
+	"deeliai/internal/service"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// Role 決定程序要啟動哪些元件，讓 API 與爬取 worker 可以分開部署、各自擴展
+type Role string
+
+const (
+	RoleAPI       Role = "api"       // 只提供 HTTP API，任務交給佇列
+	RoleWorker    Role = "worker"    // 只消化佇列中的爬取任務
+	RoleScheduler Role = "scheduler" // 只執行重試排程器 (多實例時以 advisory lock 選出一個)
+	RoleAll       Role = "all"       // 單機模式，啟動所有元件
+)
+
+// schedulerLockKey 是排程器 leader election 使用的 advisory lock key
+const schedulerLockKey int64 = 0x6465656c696169 // ASCII "deeliai"
+
+// ParseRole 解析 --role 參數
+func ParseRole(s string) (Role, error) {
+	switch r := Role(s); r {
+	case RoleAPI, RoleWorker, RoleScheduler, RoleAll:
+		return r, nil
+	}
+
+	return "", fmt.Errorf("unknown role %q, expected api|worker|scheduler|all", s)
+}
+
+func (r Role) runs(component Role) bool {
+	return r == RoleAll || r == component
+}
+
+// ConnectDB 依設定建立資料庫連線，供服務與一次性的維護指令共用
+func ConnectDB(cfg *config.Config) (*sqlx.DB, error) {
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Database.Host, cfg.Database.Port, cfg.Database.User, cfg.Database.Password, cfg.Database.DBName, cfg.Database.SSLMode)
+
+	db, err := sqlx.Connect(cfg.Database.Driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return db, nil
+}
+
+// Run 依 role 組裝並啟動元件，直到收到 SIGINT/SIGTERM 才優雅關閉
+func Run(cfg *config.Config, role Role) error {
+	// 初始化資料庫連線
+	db, err := ConnectDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// 記憶體佇列無法跨程序共享，只能在單機模式使用
+	if (cfg.Queue.Driver == "" || cfg.Queue.Driver == "channel") && role != RoleAll {
+		return fmt.Errorf("queue driver %q only supports role=all", cfg.Queue.Driver)
+	}
+
+	// 依賴注入：組裝 Repository, Service；API 相關的 Handler 在 newHTTPServer 中組裝
+	pageRepo := sqlximpl.NewPageRepository(db)
+	scrapeJobRepo := sqlximpl.NewScrapeJobRepository(db)
+	scrapeAttemptRepo := sqlximpl.NewScrapeAttemptRepository(db)
+	pageContentRepo := sqlximpl.NewPageContentRepository(db)
+
+	urlGuard, err := newURLGuard(cfg)
+	if err != nil {
+		return fmt.Errorf("invalid scrape.guard config: %w", err)
+	}
+
+	imageStore, err := newImageStore(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to set up image store: %w", err)
+	}
+
+	fetcher := newFetcher(cfg, urlGuard)
+	scrapeService := service.NewScrapeService(pageRepo, scrapeAttemptRepo, pageContentRepo, retryPolicy(cfg), fetcher, newPoliteness(cfg, fetcher), extractors.NewDefaultRegistry())
+	if imageStore != nil {
+		scrapeService.EnableImageCache(imageStore, cfg.Scrape.Images.MaxWidth, cfg.Scrape.Images.MaxHeight)
+	}
+
+	// 依設定決定佇列實作
+	producer, consumer, err := newQueue(cfg, scrapeJobRepo, scrapeService)
+	if err != nil {
+		return fmt.Errorf("failed to set up scrape queue: %w", err)
+	}
+	defer producer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var srv *http.Server
+	if role.runs(RoleAPI) {
+		srv, err = newHTTPServer(cfg, db, producer, urlGuard, imageStore)
+		if err != nil {
+			return err
+		}
+
+		// 在一個新的 goroutine 中啟動 server，避免阻塞
+		go func() {
+			slog.Info(fmt.Sprintf("Server starting on port %d", cfg.App.Port))
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("Server failed to start", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	if role.runs(RoleWorker) {
+		consumer.Start()
+		defer consumer.Stop()
+	}
+
+	if role.runs(RoleScheduler) {
+		// 啟動排程器 (僅負責生產)，多個實例同時啟動時只有取得 advisory lock 的那一個會執行
+		checkInterval := cfg.Scrape.Retry.CheckInterval
+		if checkInterval <= 0 {
+			checkInterval = time.Minute
+		}
+		refreshBatch := cfg.Scrape.Refresh.BatchSize
+		if refreshBatch <= 0 {
+			refreshBatch = 100
+		}
+		scrapeScheduler := scraper.NewScrapeScheduler(pageRepo, producer, checkInterval, cfg.Scrape.Refresh.TTL, refreshBatch)
+		lock := sqlximpl.NewAdvisoryLock(db, schedulerLockKey)
+		go scraper.RunAsLeader(ctx, lock, 30*time.Second, scrapeScheduler.Start)
+	}
+
+	slog.Info("Process started", "role", role)
+
+	// 等待中斷訊號 (SIGINT or SIGTERM)
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit // 阻塞直到接收到訊號
+	slog.Info("Shutting down...")
+
+	if srv != nil {
+		// 呼叫 server.Shutdown() 進行優雅關閉
+		// 這會等待正在處理的請求結束，但不再接受新請求
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer shutdownCancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("server forced to shutdown: %w", err)
+		}
+	}
+
+	slog.Info("Process exiting gracefully.")
+	return nil
+}
+
+// newHTTPServer 組裝 API 需要的 Service 與 Handler
+func newHTTPServer(cfg *config.Config, db *sqlx.DB, producer interfaces.QueueProducer, urlGuard *scraper.URLGuard, imageStore interfaces.BlobStore) (*http.Server, error) {
+	// 載入 JWT 簽章金鑰
+	keySet, err := loadKeySet(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jwt signing keys: %w", err)
+	}
+
+	userRepo := sqlximpl.NewUserRepository(db)
+	articleRepo := sqlximpl.NewArticleRepository(db)
+	pageRepo := sqlximpl.NewPageRepository(db)
+	ratingRepo := sqlximpl.NewRatingRepository(db)
+	tagRepo := sqlximpl.NewTagRepository(db)
+	collectionRepo := sqlximpl.NewCollectionRepository(db)
+	sessionRepo := sqlximpl.NewSessionRepository(db)
+	scrapeAttemptRepo := sqlximpl.NewScrapeAttemptRepository(db)
+	pageContentRepo := sqlximpl.NewPageContentRepository(db)
+
+	userService := service.NewUserService(userRepo)
+	authService := service.NewAuthService(keySet, cfg.JWT.Issuer, sessionRepo, cfg.App.AccessTokenTTL, cfg.App.RefreshTokenTTL)
+	articleService := service.NewArticleService(articleRepo, pageRepo, scrapeAttemptRepo, pageContentRepo, collectionRepo, imageStore, producer, urlGuard, cfg.Scrape.Refresh.TTL)
+	ratingService := service.NewRatingService(ratingRepo, tagRepo)
+	tagService := service.NewTagService(tagRepo, articleRepo)
+	collectionService := service.NewCollectionService(collectionRepo, articleRepo)
+	recommendService := service.NewRecommendService(pageRepo, ratingRepo)
+
+	userHandler := handler.NewUserHandler(userService, authService)
+	articleHandler := handler.NewArticleHandler(articleService)
+	ratingHandler := handler.NewRatingHandler(ratingService)
+	tagHandler := handler.NewTagHandler(tagService)
+	collectionHandler := handler.NewCollectionHandler(collectionService)
+	recommendHandler := handler.NewRecommendHandler(recommendService)
+
+	// 設定路由
+	// 重新爬取會對外發出請求，依使用者限制頻率 (單篇與批次共用額度)
+	rescrapeRate := cfg.Scrape.Rescrape.RatePerMinute
+	if rescrapeRate <= 0 {
+		rescrapeRate = 10
+	}
+	rescrapeBurst := cfg.Scrape.Rescrape.Burst
+	if rescrapeBurst <= 0 {
+		rescrapeBurst = 5
+	}
+	rescrapeLimiter := middleware.RateLimitByUser(rescrapeRate, rescrapeBurst)
+
+	router := handler.SetupRouter(userHandler, articleHandler, ratingHandler, tagHandler, collectionHandler, recommendHandler, rescrapeLimiter)
+	slog.Info("Router setup complete")
+
+	// 建立 HTTP Server
+	return &http.Server{
+		Addr:    ":" + strconv.Itoa(cfg.App.Port),
+		Handler: router,
+	}, nil
+}
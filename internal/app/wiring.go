@@ -0,0 +1,178 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"deeliai/config"
+	"deeliai/internal/interfaces"
+	"deeliai/internal/queue"
+	"deeliai/internal/scraper"
+	"deeliai/internal/service"
+	"deeliai/internal/storage"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// loadKeySet 依設定載入 JWT 金鑰，沒有設定任何金鑰時退回 HS256 共享密鑰
+func loadKeySet(cfg *config.Config) (*service.KeySet, error) {
+	if len(cfg.JWT.Keys) == 0 {
+		return service.NewKeySet("hs256", service.NewHMACKey("hs256", cfg.App.JWTSecret))
+	}
+
+	keys := make([]*service.SigningKey, 0, len(cfg.JWT.Keys))
+	for _, k := range cfg.JWT.Keys {
+		key, err := service.LoadSigningKey(k.KID, k.Algorithm, k.PrivateKeyFile, k.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return service.NewKeySet(cfg.JWT.ActiveKID, keys...)
+}
+
+// newQueue 依 queue.driver 建立對應的 Producer 與 Consumer
+func newQueue(cfg *config.Config, jobRepo interfaces.ScrapeJobRepository, scrapeService *service.ScrapeService) (interfaces.QueueProducer, interfaces.QueueConsumer, error) {
+	workerCount := cfg.Queue.WorkerCount
+	if workerCount <= 0 {
+		workerCount = 2
+	}
+
+	switch cfg.Queue.Driver {
+	case "", "channel":
+		bufferSize := cfg.Queue.BufferSize
+		if bufferSize <= 0 {
+			bufferSize = 100
+		}
+
+		// 建立一個有緩衝的爬取任務佇列
+		scrapeQueue := make(chan string, bufferSize)
+		return queue.NewChannelProducer(scrapeQueue), queue.NewChannelConsumer(scrapeQueue, scrapeService, workerCount), nil
+	case "postgres":
+		pollInterval := cfg.Queue.PollInterval
+		if pollInterval <= 0 {
+			pollInterval = 2 * time.Second
+		}
+		visibilityTimeout := cfg.Queue.VisibilityTimeout
+		if visibilityTimeout <= 0 {
+			visibilityTimeout = 2 * time.Minute
+		}
+
+		return queue.NewPostgresProducer(jobRepo),
+			queue.NewPostgresConsumer(jobRepo, scrapeService, workerCount, pollInterval, visibilityTimeout, cfg.Queue.MaxDeliveries),
+			nil
+	case "redis":
+		rc := cfg.Queue.Redis
+		client := redis.NewClient(&redis.Options{
+			Addr:     rc.Addr,
+			Password: rc.Password,
+			DB:       rc.DB,
+		})
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to redis: %w", err)
+		}
+
+		opts := queue.RedisConsumerOptions{
+			Stream:        rc.Stream,
+			Group:         rc.Group,
+			WorkerCount:   workerCount,
+			Block:         rc.Block,
+			ClaimMinIdle:  rc.ClaimMinIdle,
+			ClaimInterval: rc.ClaimInterval,
+			MaxDeliveries: int64(cfg.Queue.MaxDeliveries),
+		}
+		if opts.Stream == "" {
+			opts.Stream = "deeliai:scrape"
+		}
+		if opts.Group == "" {
+			opts.Group = "scrape-workers"
+		}
+		if opts.Block <= 0 {
+			opts.Block = 5 * time.Second
+		}
+		if opts.ClaimMinIdle <= 0 {
+			opts.ClaimMinIdle = 2 * time.Minute
+		}
+		if opts.ClaimInterval <= 0 {
+			opts.ClaimInterval = 30 * time.Second
+		}
+
+		return queue.NewRedisProducer(client, opts.Stream, rc.MaxLen), queue.NewRedisConsumer(client, scrapeService, opts), nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown queue driver %q", cfg.Queue.Driver)
+}
+
+// retryPolicy 依設定建立爬取重試策略，未設定的欄位沿用預設值
+func retryPolicy(cfg *config.Config) service.RetryPolicy {
+	policy := service.DefaultRetryPolicy
+	if cfg.Scrape.Retry.MaxAttempts > 0 {
+		policy.MaxAttempts = cfg.Scrape.Retry.MaxAttempts
+	}
+	if cfg.Scrape.Retry.BaseDelay > 0 {
+		policy.BaseDelay = cfg.Scrape.Retry.BaseDelay
+	}
+	if cfg.Scrape.Retry.MaxDelay > 0 {
+		policy.MaxDelay = cfg.Scrape.Retry.MaxDelay
+	}
+
+	return policy
+}
+
+// newURLGuard 依設定建立爬取目標的位址檢查
+func newURLGuard(cfg *config.Config) (*scraper.URLGuard, error) {
+	return scraper.NewURLGuard(cfg.Scrape.Guard.Allow, cfg.Scrape.Guard.Deny)
+}
+
+// newFetcher 依設定建立爬取用的 Fetcher，未設定的欄位由 scraper 套用預設值
+func newFetcher(cfg *config.Config, guard *scraper.URLGuard) *scraper.Fetcher {
+	fc := cfg.Scrape.Fetcher
+	return scraper.NewFetcher(scraper.FetcherOptions{
+		ConnectTimeout: fc.ConnectTimeout,
+		ReadTimeout:    fc.ReadTimeout,
+		MaxBodyBytes:   fc.MaxBodyBytes,
+		MaxRedirects:   fc.MaxRedirects,
+		UserAgent:      fc.UserAgent,
+		Guard:          guard,
+	})
+}
+
+// newImageStore 依設定建立存放預覽圖縮圖的 BlobStore，未啟用時回傳 nil
+func newImageStore(cfg *config.Config) (interfaces.BlobStore, error) {
+	ic := &cfg.Scrape.Images
+	if !ic.Enabled {
+		return nil, nil
+	}
+
+	if ic.Dir == "" {
+		ic.Dir = "./data/blobs"
+	}
+	if ic.MaxWidth <= 0 {
+		ic.MaxWidth = 600
+	}
+	if ic.MaxHeight <= 0 {
+		ic.MaxHeight = 600
+	}
+
+	return storage.NewLocalStore(ic.Dir)
+}
+
+// newPoliteness 依設定建立禮貌爬取規則，robots.txt 透過同一個 Fetcher 抓取
+func newPoliteness(cfg *config.Config, fetcher *scraper.Fetcher) *scraper.Politeness {
+	pc := cfg.Scrape.Politeness
+	opts := scraper.PolitenessOptions{
+		RespectRobots:  pc.RespectRobots == nil || *pc.RespectRobots,
+		UserAgent:      cfg.Scrape.Fetcher.UserAgent,
+		MaxConcurrency: pc.MaxConcurrency,
+		MinDelay:       pc.MinDelay,
+		MaxWait:        pc.MaxWait,
+		RobotsCacheTTL: pc.RobotsCacheTTL,
+	}
+	if opts.MaxWait == 0 {
+		opts.MaxWait = scraper.DefaultPolitenessOptions.MaxWait
+	}
+
+	return scraper.NewPoliteness(fetcher, opts)
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"database/sql"
 	"errors"
 	"net/http"
-	"strconv"
 
+	"deeliai/internal/model"
+	"deeliai/internal/scraper"
 	"deeliai/internal/service"
+	"deeliai/internal/urlnorm"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -20,14 +23,15 @@ func NewArticleHandler(s *service.ArticleService) *ArticleHandler {
 }
 
 // @Summary 提交新文章
-// @Description 提交一個文章 URL，後台會自動爬取 metadata
+// @Description 提交一個文章 URL，後台會自動爬取 metadata；網址會先正規化 (忽略 utm_* 等追蹤參數、結尾斜線、http/https 與 www./m. 子網域)，重複收藏時回傳既有文章
 // @Tags articles
 // @Security BearerAuth
 // @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
 // @Param request body PostArticleRequest true "文章 URL"
 // @Accept json
 // @Produce json
-// @Success 202 {object} StandardResponse{data=model.Article} "文章正在處理中"
+// @Success 201 {object} StandardResponse{data=PostArticleResponse} "文章正在處理中"
+// @Success 200 {object} StandardResponse{data=PostArticleResponse} "已收藏過相同網址，回傳既有文章 (duplicate 為 true)"
 // @Failure 400 {object} ErrorResponse "無效的請求或 URL"
 // @Failure 401 {object} ErrorResponse "未授權"
 // @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
@@ -45,24 +49,48 @@ func (h *ArticleHandler) PostArticle(c *gin.Context) {
 		return
 	}
 
-	article, err := h.articleService.CreateArticle(c.Request.Context(), req.URL, emailAny.(string))
+	article, duplicate, err := h.articleService.CreateArticle(c.Request.Context(), req.URL, emailAny.(string))
 	if err != nil {
+		if errors.Is(err, scraper.ErrBlockedAddress) || errors.Is(err, scraper.ErrUnsupportedScheme) || errors.Is(err, urlnorm.ErrInvalidURL) {
+			RespondWithError(c, http.StatusBadRequest, err, "URL is not allowed")
+			return
+		}
+
 		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
 		return
 	}
 
-	RespondWithSuccess(c, http.StatusCreated, "Post success", article)
+	if duplicate {
+		RespondWithSuccess(c, http.StatusOK, "Article already saved", PostArticleResponse{Article: article, Duplicate: true})
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusCreated, "Post success", PostArticleResponse{Article: article})
 }
 
 // @Summary 獲取文章列表
-// @Description 獲取使用者收藏的文章列表
+// @Description 以 keyset 分頁取得使用者收藏的文章列表：第一頁不帶 cursor，之後帶上前一頁回傳的 next_cursor，換頁期間新增的文章不會造成重複或遺漏
+// @Description cursor 綁定排序方式，更換 sort/order 時需從第一頁重新開始
 // @Tags articles
 // @Security BearerAuth
 // @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
 // @Produce json
-// @Param limit query int false "限制返回數量" default(10)
-// @Param offset query int false "跳過數量" default(0)
-// @Success 200 {object} StandardResponse{data=[]model.Article} "成功獲取文章列表"
+// @Param limit query int false "每頁數量 (1-100)" default(10)
+// @Param cursor query string false "前一頁回傳的 next_cursor"
+// @Param sort query string false "排序欄位" Enums(created_at, rating, title) default(created_at)
+// @Param order query string false "排序方向，預設日期與評分為 desc、標題為 asc" Enums(asc, desc)
+// @Param status query string false "爬取狀態" Enums(pending, success, failed, failed_permanent, deferred)
+// @Param domain query string false "網域，包含子網域"
+// @Param tag query string false "標籤名稱 (不分大小寫)"
+// @Param min_rating query int false "評分下限 (1-5)"
+// @Param max_rating query int false "評分上限 (1-5)"
+// @Param created_after query string false "收藏時間下限 (RFC 3339)"
+// @Param created_before query string false "收藏時間上限 (RFC 3339，不含)"
+// @Param read query bool false "是否已讀"
+// @Param archived query string false "是否已封存，預設只列出未封存的文章" Enums(false, true, all) default(false)
+// @Param favorited query bool false "是否加入最愛"
+// @Success 200 {object} StandardResponse{data=ArticleListResponse} "成功獲取文章列表"
+// @Failure 400 {object} ErrorResponse "無效的查詢參數或 cursor"
 // @Failure 401 {object} ErrorResponse "未授權"
 // @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
 // @Router /articles [get]
@@ -73,16 +101,277 @@ func (h *ArticleHandler) GetArticles(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	var req ListArticlesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid query parameters")
+		return
+	}
+	if req.Sort == model.ArticleSortPosition {
+		RespondWithError(c, http.StatusBadRequest, errors.New("sort=position is only available in collections"), "Invalid query parameters")
+		return
+	}
+
+	query := articleQuery(req, model.ArticleSortCreatedAt)
+	list, err := h.articleService.GetArticles(c.Request.Context(), emailAny.(string), query, req.Cursor)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Get success", ArticleListResponse{
+		Articles:   list.Articles,
+		NextCursor: list.NextCursor,
+		Total:      list.Total,
+	})
+}
+
+// articleQuery 將列表的查詢參數轉為 model.ArticleQuery，sort 未指定時使用 defaultSort
+func articleQuery(req ListArticlesRequest, defaultSort string) model.ArticleQuery {
+	query := model.ArticleQuery{
+		Filter: model.ArticleFilter{
+			Status:        req.Status,
+			Domain:        req.Domain,
+			Tag:           req.Tag,
+			MinRating:     req.MinRating,
+			MaxRating:     req.MaxRating,
+			CreatedAfter:  req.CreatedAfter,
+			CreatedBefore: req.CreatedBefore,
+			Read:          req.Read,
+			Favorited:     req.Favorited,
+		},
+		Sort:  req.Sort,
+		Limit: req.Limit,
+	}
+	if query.Sort == "" {
+		query.Sort = defaultSort
+	}
+	if query.Limit == 0 {
+		query.Limit = 10
+	}
+	// 預設只列出未封存的文章，all 代表不限制
+	if req.Archived != "all" {
+		archived := req.Archived == "true"
+		query.Filter.Archived = &archived
+	}
+	switch req.Order {
+	case "asc":
+		query.Desc = false
+	case "desc":
+		query.Desc = true
+	default:
+		query.Desc = query.Sort != model.ArticleSortTitle && query.Sort != model.ArticleSortPosition
+	}
+
+	return query
+}
+
+// @Summary 搜尋文章
+// @Description 以全文搜尋使用者收藏的文章 (標題、描述、網址、筆記與標籤)，依相關度排序；支援 websearch 語法 (例如 "片語"、-排除、or)
+// @Description 中文等沒有空白分詞的內容會以部分字串比對標題與描述；snippet 中符合的字詞以 <mark></mark> 標示
+// @Tags articles
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Produce json
+// @Param q query string true "搜尋關鍵字"
+// @Param limit query int false "限制返回數量 (1-100)" default(20)
+// @Param offset query int false "跳過數量" default(0)
+// @Success 200 {object} StandardResponse{data=[]model.ArticleSearchResult} "搜尋結果"
+// @Failure 400 {object} ErrorResponse "無效的查詢參數"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /articles/search [get]
+func (h *ArticleHandler) SearchArticles(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	var req SearchArticlesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid query parameters")
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	results, err := h.articleService.SearchArticles(c.Request.Context(), emailAny.(string), req.Q, req.Limit, req.Offset)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Search success", results)
+}
+
+// @Summary 修改文章
+// @Description 修改使用者自訂的標題、描述與筆記，自訂值優先於爬取到的值且重新爬取時不會被覆蓋；只會修改有帶上的欄位，空字串代表清除自訂值
+// @Tags articles
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Param id path string true "文章 ID"
+// @Param request body PatchArticleRequest true "要修改的欄位"
+// @Accept json
+// @Produce json
+// @Success 200 {object} StandardResponse{data=model.Article} "文章修改成功"
+// @Failure 400 {object} ErrorResponse "無效的請求或文章 ID"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "文章不存在"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /articles/{id} [patch]
+func (h *ArticleHandler) PatchArticle(c *gin.Context) {
+	articleID := c.Param("id")
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	articleUUID, err := uuid.Parse(articleID)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid article id")
+		return
+	}
+
+	var req PatchArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	update := model.ArticleUpdate{
+		CustomTitle:       req.CustomTitle,
+		CustomDescription: req.CustomDescription,
+		Notes:             req.Notes,
+	}
+	article, err := h.articleService.UpdateArticle(c.Request.Context(), articleUUID, emailAny.(string), update)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondWithError(c, http.StatusNotFound, err, "Article not found")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Update success", article)
+}
+
+// @Summary 修改文章的閱讀狀態
+// @Description 標記文章為已讀/未讀、封存/取消封存、加入/移除最愛，只會修改有帶上的欄位；重複標記為已讀或封存時保留第一次的時間
+// @Tags articles
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Accept json
+// @Produce json
+// @Param id path string true "文章 ID"
+// @Param request body ArticleStateRequest true "要修改的狀態"
+// @Success 200 {object} StandardResponse{data=model.Article} "修改成功"
+// @Failure 400 {object} ErrorResponse "無效的請求"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "文章不存在"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /articles/{id}/state [patch]
+func (h *ArticleHandler) PatchArticleState(c *gin.Context) {
+	articleID := c.Param("id")
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	articleUUID, err := uuid.Parse(articleID)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid article id")
+		return
+	}
 
-	articles, err := h.articleService.GetArticles(c.Request.Context(), emailAny.(string), page, limit)
+	var req ArticleStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	update := model.ArticleStateUpdate{
+		Read:      req.Read,
+		Archived:  req.Archived,
+		Favorited: req.Favorited,
+	}
+	article, err := h.articleService.UpdateArticleState(c.Request.Context(), articleUUID, emailAny.(string), update)
 	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNoStateChange):
+			RespondWithError(c, http.StatusBadRequest, err, "At least one of read, archived or favorited is required")
+		case errors.Is(err, sql.ErrNoRows):
+			RespondWithError(c, http.StatusNotFound, err, "Article not found")
+		default:
+			RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		}
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Update success", article)
+}
+
+// @Summary 批次修改文章的閱讀狀態
+// @Description 一次修改多篇文章 (最多 500 篇) 的已讀、封存與最愛狀態，只會修改有帶上的欄位，不屬於使用者的文章會被略過
+// @Tags articles
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Accept json
+// @Produce json
+// @Param request body BulkArticleStateRequest true "文章 ID 與要修改的狀態"
+// @Success 200 {object} StandardResponse{data=object{updated=int}} "實際修改的文章數"
+// @Failure 400 {object} ErrorResponse "無效的請求"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /articles/state [post]
+func (h *ArticleHandler) BulkUpdateArticleState(c *gin.Context) {
+	var req BulkArticleStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	articleUUIDs := make([]uuid.UUID, len(req.ArticleIDs))
+	for i, id := range req.ArticleIDs {
+		articleUUID, err := uuid.Parse(id)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, err, "Invalid article id")
+			return
+		}
+		articleUUIDs[i] = articleUUID
+	}
+	update := model.ArticleStateUpdate{
+		Read:      req.Read,
+		Archived:  req.Archived,
+		Favorited: req.Favorited,
+	}
+	updated, err := h.articleService.BulkUpdateArticleState(c.Request.Context(), emailAny.(string), articleUUIDs, update)
+	if err != nil {
+		if errors.Is(err, service.ErrNoStateChange) {
+			RespondWithError(c, http.StatusBadRequest, err, "At least one of read, archived or favorited is required")
+			return
+		}
+
 		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
 		return
 	}
 
-	RespondWithSuccess(c, http.StatusOK, "Get success", articles)
+	RespondWithSuccess(c, http.StatusOK, "Update success", gin.H{"updated": updated})
 }
 
 // @Summary 刪除文章
@@ -119,3 +408,213 @@ func (h *ArticleHandler) DeleteArticle(c *gin.Context) {
 
 	RespondWithSuccess(c, http.StatusOK, "Delete success", nil)
 }
+
+// @Summary 獲取文章的爬取紀錄
+// @Description 列出文章最近的爬取嘗試 (新的在前)，包含 HTTP 狀態碼、錯誤分類、錯誤訊息、耗時與最終 URL
+// @Tags articles
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Param id path string true "文章 ID"
+// @Produce json
+// @Success 200 {object} StandardResponse{data=[]model.ScrapeAttempt} "成功獲取爬取紀錄"
+// @Failure 400 {object} ErrorResponse "無效的文章 ID"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "文章不存在"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /articles/{id}/scrape-attempts [get]
+func (h *ArticleHandler) GetScrapeAttempts(c *gin.Context) {
+	articleID := c.Param("id")
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	articleUUID, err := uuid.Parse(articleID)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid article id")
+		return
+	}
+
+	attempts, err := h.articleService.ListScrapeAttempts(c.Request.Context(), articleUUID, emailAny.(string))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondWithError(c, http.StatusNotFound, err, "Article not found")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Get success", attempts)
+}
+
+// @Summary 獲取文章內文
+// @Description 取得爬取時擷取出的文章主體 (已 sanitize 的 HTML 與純文字)，以及字數與預估閱讀時間
+// @Tags articles
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Param id path string true "文章 ID"
+// @Produce json
+// @Success 200 {object} StandardResponse{data=model.PageContent} "成功獲取文章內文"
+// @Failure 400 {object} ErrorResponse "無效的文章 ID"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "文章不存在或尚未擷取內文"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /articles/{id}/content [get]
+func (h *ArticleHandler) GetArticleContent(c *gin.Context) {
+	articleID := c.Param("id")
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	articleUUID, err := uuid.Parse(articleID)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid article id")
+		return
+	}
+
+	content, err := h.articleService.GetArticleContent(c.Request.Context(), articleUUID, emailAny.(string))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondWithError(c, http.StatusNotFound, err, "Article content not found")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Get success", content)
+}
+
+// @Summary 獲取文章預覽圖
+// @Description 回傳快取的預覽圖縮圖 (JPEG)；尚未快取時回傳 404，前端可改用文章的 image_url
+// @Tags articles
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Param id path string true "文章 ID"
+// @Produce jpeg
+// @Success 200 {file} binary "縮圖"
+// @Failure 400 {object} ErrorResponse "無效的文章 ID"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "文章不存在或尚未快取預覽圖"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /articles/{id}/image [get]
+func (h *ArticleHandler) GetArticleImage(c *gin.Context) {
+	articleID := c.Param("id")
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	articleUUID, err := uuid.Parse(articleID)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid article id")
+		return
+	}
+
+	thumbnail, err := h.articleService.GetArticleImage(c.Request.Context(), articleUUID, emailAny.(string))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondWithError(c, http.StatusNotFound, err, "Article image not found")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	defer thumbnail.Close()
+
+	// 縮圖內容只會在重新爬取時改變，允許瀏覽器私有快取
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.DataFromReader(http.StatusOK, -1, "image/jpeg", thumbnail, nil)
+}
+
+// @Summary 重新爬取文章
+// @Description 重設文章的爬取狀態與重試次數並重新排入佇列，可用來更新已變動的 metadata
+// @Tags articles
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Param id path string true "文章 ID"
+// @Produce json
+// @Success 202 {object} StandardResponse{data=model.Article} "文章已重新排入爬取佇列"
+// @Failure 400 {object} ErrorResponse "無效的文章 ID"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "文章不存在"
+// @Failure 429 {object} ErrorResponse "請求過於頻繁"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /articles/{id}/rescrape [post]
+func (h *ArticleHandler) RescrapeArticle(c *gin.Context) {
+	articleID := c.Param("id")
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	articleUUID, err := uuid.Parse(articleID)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid article id")
+		return
+	}
+
+	article, err := h.articleService.RescrapeArticle(c.Request.Context(), articleUUID, emailAny.(string))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondWithError(c, http.StatusNotFound, err, "Article not found")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusAccepted, "Rescrape queued", article)
+}
+
+// @Summary 批次重新爬取文章
+// @Description 依爬取狀態、網域與最後更新時間篩選文章，將所屬頁面重新排入佇列，單次最多 500 個頁面
+// @Tags articles
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Param request body BulkRescrapeRequest true "篩選條件"
+// @Accept json
+// @Produce json
+// @Success 202 {object} StandardResponse{data=object{queued=int}} "文章已重新排入爬取佇列"
+// @Failure 400 {object} ErrorResponse "無效的請求"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 429 {object} ErrorResponse "請求過於頻繁"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /articles/rescrape [post]
+func (h *ArticleHandler) BulkRescrapeArticles(c *gin.Context) {
+	var req BulkRescrapeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	filter := model.RescrapeFilter{
+		Status:    req.Status,
+		Domain:    req.Domain,
+		OlderThan: req.OlderThan,
+	}
+	queued, err := h.articleService.BulkRescrapeArticles(c.Request.Context(), emailAny.(string), filter)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusAccepted, "Rescrape queued", gin.H{"queued": queued})
+}
@@ -0,0 +1,451 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"deeliai/internal/interfaces"
+	"deeliai/internal/model"
+	"deeliai/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CollectionHandler struct {
+	collectionService *service.CollectionService
+}
+
+func NewCollectionHandler(s *service.CollectionService) *CollectionHandler {
+	return &CollectionHandler{collectionService: s}
+}
+
+// @Summary 獲取收藏夾列表
+// @Description 列出使用者所有收藏夾與各自的文章數，預設的 Inbox 排在最前面
+// @Tags collections
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Produce json
+// @Success 200 {object} StandardResponse{data=[]model.Collection} "成功獲取收藏夾列表"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /collections [get]
+func (h *CollectionHandler) GetCollections(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	collections, err := h.collectionService.ListCollections(c.Request.Context(), emailAny.(string))
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Get success", collections)
+}
+
+// @Summary 建立收藏夾
+// @Description 建立新的收藏夾，名稱不可與使用者其他收藏夾重複
+// @Tags collections
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Accept json
+// @Produce json
+// @Param request body CreateCollectionRequest true "收藏夾名稱與描述"
+// @Success 201 {object} StandardResponse{data=model.Collection} "建立成功"
+// @Failure 400 {object} ErrorResponse "無效的請求"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 409 {object} ErrorResponse "已有同名的收藏夾"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /collections [post]
+func (h *CollectionHandler) PostCollection(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	var req CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	collection, err := h.collectionService.CreateCollection(c.Request.Context(), emailAny.(string), req.Name, req.Description)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCollectionName):
+			RespondWithError(c, http.StatusBadRequest, err, "Invalid collection name")
+		case errors.Is(err, interfaces.ErrCollectionExists):
+			RespondWithError(c, http.StatusConflict, err, "Collection already exists")
+		default:
+			RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		}
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusCreated, "Post success", collection)
+}
+
+// @Summary 獲取收藏夾
+// @Description 取得單一收藏夾的資訊與文章數
+// @Tags collections
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Produce json
+// @Param id path string true "收藏夾 ID"
+// @Success 200 {object} StandardResponse{data=model.Collection} "成功獲取收藏夾"
+// @Failure 400 {object} ErrorResponse "無效的收藏夾 ID"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "收藏夾不存在"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /collections/{id} [get]
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	collectionUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid collection id")
+		return
+	}
+
+	collection, err := h.collectionService.GetCollection(c.Request.Context(), collectionUUID, emailAny.(string))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondWithError(c, http.StatusNotFound, err, "Collection not found")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Get success", collection)
+}
+
+// @Summary 修改收藏夾
+// @Description 修改收藏夾的名稱或描述，只會修改有帶上的欄位；description 傳入空字串代表清除
+// @Tags collections
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Accept json
+// @Produce json
+// @Param id path string true "收藏夾 ID"
+// @Param request body PatchCollectionRequest true "要修改的欄位"
+// @Success 200 {object} StandardResponse{data=model.Collection} "修改成功"
+// @Failure 400 {object} ErrorResponse "無效的請求"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "收藏夾不存在"
+// @Failure 409 {object} ErrorResponse "已有同名的收藏夾"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /collections/{id} [patch]
+func (h *CollectionHandler) PatchCollection(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	collectionUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid collection id")
+		return
+	}
+
+	var req PatchCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	update := model.CollectionUpdate{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	collection, err := h.collectionService.UpdateCollection(c.Request.Context(), collectionUUID, emailAny.(string), update)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCollectionName):
+			RespondWithError(c, http.StatusBadRequest, err, "Invalid collection name")
+		case errors.Is(err, sql.ErrNoRows):
+			RespondWithError(c, http.StatusNotFound, err, "Collection not found")
+		case errors.Is(err, interfaces.ErrCollectionExists):
+			RespondWithError(c, http.StatusConflict, err, "Collection already exists")
+		default:
+			RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		}
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Update success", collection)
+}
+
+// @Summary 刪除收藏夾
+// @Description 刪除收藏夾，其中的文章仍保留在使用者的收藏中；預設的 Inbox 不能刪除
+// @Tags collections
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Param id path string true "收藏夾 ID"
+// @Success 200 {object} StandardResponse "刪除成功"
+// @Failure 400 {object} ErrorResponse "無效的收藏夾 ID 或為預設收藏夾"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "收藏夾不存在"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /collections/{id} [delete]
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	collectionUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid collection id")
+		return
+	}
+
+	err = h.collectionService.DeleteCollection(c.Request.Context(), collectionUUID, emailAny.(string))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDefaultCollection):
+			RespondWithError(c, http.StatusBadRequest, err, "Default collection cannot be deleted")
+		case errors.Is(err, sql.ErrNoRows):
+			RespondWithError(c, http.StatusNotFound, err, "Collection not found")
+		default:
+			RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		}
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Delete success", nil)
+}
+
+// @Summary 獲取收藏夾中的文章
+// @Description 以與文章列表相同的 keyset 分頁與篩選條件取得收藏夾中的文章，預設依收藏夾中的順序 (sort=position) 由前到後排列
+// @Tags collections
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Produce json
+// @Param id path string true "收藏夾 ID"
+// @Param limit query int false "每頁數量 (1-100)" default(10)
+// @Param cursor query string false "前一頁回傳的 next_cursor"
+// @Param sort query string false "排序欄位" Enums(position, created_at, rating, title) default(position)
+// @Param order query string false "排序方向，預設日期與評分為 desc、標題與順序為 asc" Enums(asc, desc)
+// @Param status query string false "爬取狀態" Enums(pending, success, failed, failed_permanent, deferred)
+// @Param domain query string false "網域，包含子網域"
+// @Param tag query string false "標籤名稱 (不分大小寫)"
+// @Param min_rating query int false "評分下限 (1-5)"
+// @Param max_rating query int false "評分上限 (1-5)"
+// @Param created_after query string false "收藏時間下限 (RFC 3339)"
+// @Param created_before query string false "收藏時間上限 (RFC 3339，不含)"
+// @Param read query bool false "是否已讀"
+// @Param archived query string false "是否已封存，預設只列出未封存的文章" Enums(false, true, all) default(false)
+// @Param favorited query bool false "是否加入最愛"
+// @Success 200 {object} StandardResponse{data=ArticleListResponse} "成功獲取文章列表"
+// @Failure 400 {object} ErrorResponse "無效的查詢參數或 cursor"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "收藏夾不存在"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /collections/{id}/articles [get]
+func (h *CollectionHandler) GetCollectionArticles(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	collectionUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid collection id")
+		return
+	}
+
+	var req ListArticlesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid query parameters")
+		return
+	}
+
+	query := articleQuery(req, model.ArticleSortPosition)
+	list, err := h.collectionService.ListCollectionArticles(c.Request.Context(), collectionUUID, emailAny.(string), query, req.Cursor)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCursor):
+			RespondWithError(c, http.StatusBadRequest, err, "Invalid cursor")
+		case errors.Is(err, sql.ErrNoRows):
+			RespondWithError(c, http.StatusNotFound, err, "Collection not found")
+		default:
+			RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		}
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Get success", ArticleListResponse{
+		Articles:   list.Articles,
+		NextCursor: list.NextCursor,
+		Total:      list.Total,
+	})
+}
+
+// @Summary 將文章加到收藏夾
+// @Description 將使用者的文章加到收藏夾的最後面，文章已在收藏夾中時不做任何事；一篇文章可以放在多個收藏夾中
+// @Tags collections
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Accept json
+// @Produce json
+// @Param id path string true "收藏夾 ID"
+// @Param request body AddCollectionArticleRequest true "文章 ID"
+// @Success 200 {object} StandardResponse "加入成功"
+// @Failure 400 {object} ErrorResponse "無效的請求"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "收藏夾或文章不存在"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /collections/{id}/articles [post]
+func (h *CollectionHandler) PostCollectionArticle(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	collectionUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid collection id")
+		return
+	}
+
+	var req AddCollectionArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	articleUUID, err := uuid.Parse(req.ArticleID)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid article id")
+		return
+	}
+
+	err = h.collectionService.AddArticle(c.Request.Context(), collectionUUID, articleUUID, emailAny.(string))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondWithError(c, http.StatusNotFound, err, "Collection or article not found")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Post success", nil)
+}
+
+// @Summary 調整文章在收藏夾中的順序
+// @Description 將文章移到收藏夾中的第 position 個 (從 1 開始，超過文章數時移到最後)，其餘文章維持原本的相對順序
+// @Tags collections
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Accept json
+// @Produce json
+// @Param id path string true "收藏夾 ID"
+// @Param article_id path string true "文章 ID"
+// @Param request body MoveCollectionArticleRequest true "新的位置"
+// @Success 200 {object} StandardResponse "修改成功"
+// @Failure 400 {object} ErrorResponse "無效的請求"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "收藏夾不存在或文章不在收藏夾中"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /collections/{id}/articles/{article_id} [patch]
+func (h *CollectionHandler) MoveCollectionArticle(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	collectionUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid collection id")
+		return
+	}
+
+	articleUUID, err := uuid.Parse(c.Param("article_id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid article id")
+		return
+	}
+
+	var req MoveCollectionArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	err = h.collectionService.MoveArticle(c.Request.Context(), collectionUUID, articleUUID, emailAny.(string), req.Position)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondWithError(c, http.StatusNotFound, err, "Collection or article not found")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Update success", nil)
+}
+
+// @Summary 將文章移出收藏夾
+// @Description 將文章移出收藏夾，文章本身仍保留在使用者的收藏中
+// @Tags collections
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Param id path string true "收藏夾 ID"
+// @Param article_id path string true "文章 ID"
+// @Success 200 {object} StandardResponse "移除成功"
+// @Failure 400 {object} ErrorResponse "無效的收藏夾或文章 ID"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "收藏夾不存在或文章不在收藏夾中"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /collections/{id}/articles/{article_id} [delete]
+func (h *CollectionHandler) DeleteCollectionArticle(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	collectionUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid collection id")
+		return
+	}
+
+	articleUUID, err := uuid.Parse(c.Param("article_id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid article id")
+		return
+	}
+
+	err = h.collectionService.RemoveArticle(c.Request.Context(), collectionUUID, articleUUID, emailAny.(string))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondWithError(c, http.StatusNotFound, err, "Collection or article not found")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Delete success", nil)
+}
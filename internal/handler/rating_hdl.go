@@ -19,7 +19,7 @@ func NewRatingHandler(s *service.RatingService) *RatingHandler {
 }
 
 // @Summary 評分並標記文章
-// @Description 為指定文章評分並新增標籤
+// @Description 為指定文章評分；帶上 tags 時同時將文章的標籤替換為 tags，省略時不修改標籤
 // @Tags ratings
 // @Security BearerAuth
 // @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
@@ -52,13 +52,13 @@ func (h *RatingHandler) RateArticle(c *gin.Context) {
 		return
 	}
 
-	if len(req.Tags) < 1 {
-		RespondWithError(c, http.StatusBadRequest, err, "At least 1 tag")
-		return
-	}
-
 	rating, err := h.ratingService.RateArticle(c.Request.Context(), emailAny.(string), articleUUID, req.Scores, req.Tags)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidTag) {
+			RespondWithError(c, http.StatusBadRequest, err, "Invalid tag name")
+			return
+		}
+
 		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
 		return
 	}
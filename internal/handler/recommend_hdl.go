@@ -23,7 +23,7 @@ func NewRecommendHandler(s *service.RecommendService) *RecommendHandler {
 // @Security BearerAuth
 // @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
 // @Produce json
-// @Success 200 {object} StandardResponse{data=[]model.Article} "成功獲取推薦文章列表"
+// @Success 200 {object} StandardResponse{data=[]model.Page} "成功獲取推薦頁面列表 (使用者尚未收藏的網址)"
 // @Failure 401 {object} ErrorResponse "未授權，JWT 驗證失敗"
 // @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
 // @Router /recommendations [get]
@@ -1,16 +1,103 @@
 package handler
 
+import "time"
+
 // SignupRequest 定義了建立使用者時的請求體結構
 type SignupRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=8"`
 }
 
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
 type PostArticleRequest struct {
 	URL string `json:"url" binding:"required,url"`
 }
 
+// ListArticlesRequest 是文章列表的查詢參數，order 未指定時日期與評分由新到舊 (高到低)、標題由 A 到 Z、收藏夾中的順序由前到後
+type ListArticlesRequest struct {
+	Limit         int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor        string     `form:"cursor"`
+	Sort          string     `form:"sort" binding:"omitempty,oneof=created_at rating title position"` // position 只能用在收藏夾
+	Order         string     `form:"order" binding:"omitempty,oneof=asc desc"`
+	Status        string     `form:"status" binding:"omitempty,oneof=pending success failed failed_permanent deferred"`
+	Domain        string     `form:"domain" binding:"omitempty,hostname"`
+	Tag           string     `form:"tag"`
+	MinRating     int        `form:"min_rating" binding:"omitempty,min=1,max=5"`
+	MaxRating     int        `form:"max_rating" binding:"omitempty,min=1,max=5"`
+	CreatedAfter  *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Read          *bool      `form:"read"`
+	Archived      string     `form:"archived" binding:"omitempty,oneof=true false all"` // 未指定時只列出未封存的文章
+	Favorited     *bool      `form:"favorited"`
+}
+
+// SearchArticlesRequest 是全文搜尋的查詢參數，limit 未指定時為 20
+type SearchArticlesRequest struct {
+	Q      string `form:"q" binding:"required,max=200"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+// PatchArticleRequest 只會修改有帶上的欄位，傳入空字串代表清除自訂值、改用爬取到的值
+type PatchArticleRequest struct {
+	CustomTitle       *string `json:"custom_title" binding:"omitempty,max=255"`
+	CustomDescription *string `json:"custom_description" binding:"omitempty,max=10000"`
+	Notes             *string `json:"notes" binding:"omitempty,max=10000"`
+}
+
+type CreateCollectionRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=1000"`
+}
+
+// PatchCollectionRequest 只會修改有帶上的欄位，description 傳入空字串代表清除
+type PatchCollectionRequest struct {
+	Name        *string `json:"name" binding:"omitempty,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=1000"`
+}
+
+type AddCollectionArticleRequest struct {
+	ArticleID string `json:"article_id" binding:"required,uuid"`
+}
+
+// MoveCollectionArticleRequest 的 position 從 1 開始，超過文章數時移到最後
+type MoveCollectionArticleRequest struct {
+	Position int `json:"position" binding:"required,min=1"`
+}
+
+// ArticleStateRequest 只會修改有帶上的欄位
+type ArticleStateRequest struct {
+	Read      *bool `json:"read"`
+	Archived  *bool `json:"archived"`
+	Favorited *bool `json:"favorited"`
+}
+
+type BulkArticleStateRequest struct {
+	ArticleIDs []string `json:"article_ids" binding:"required,min=1,max=500,dive,uuid"`
+	Read       *bool    `json:"read"`
+	Archived   *bool    `json:"archived"`
+	Favorited  *bool    `json:"favorited"`
+}
+
+type BulkRescrapeRequest struct {
+	Status    string     `json:"status" binding:"omitempty,oneof=success failed failed_permanent deferred"` // 待爬取的頁面已在佇列中，不會重新排入
+	Domain    string     `json:"domain" binding:"omitempty,hostname"`
+	OlderThan *time.Time `json:"older_than"` // RFC 3339，只重新爬取最後更新時間早於此時間的文章
+}
+
+// RateArticleRequest 帶上 tags 時會將文章的標籤替換為 tags，省略時不修改標籤
 type RateArticleRequest struct {
 	Scores int      `json:"scores" binding:"required,gte=1,lte=5"`
-	Tags   []string `json:"tags" binding:"required"`
+	Tags   []string `json:"tags" binding:"omitempty,max=20"`
+}
+
+type AddArticleTagsRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1,max=20"`
+}
+
+type RenameTagRequest struct {
+	Name string `json:"name" binding:"required"`
 }
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"deeliai/internal/model"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -31,3 +33,16 @@ func RespondWithError(c *gin.Context, httpStatus int, err error, message string)
 		Message: message,
 	})
 }
+
+// PostArticleResponse 是提交文章的回應，Duplicate 為 true 代表使用者已收藏過相同網址
+type PostArticleResponse struct {
+	*model.Article
+	Duplicate bool `json:"duplicate"`
+}
+
+// ArticleListResponse 是文章列表的回應，NextCursor 為空代表沒有下一頁
+type ArticleListResponse struct {
+	Articles   []model.Article `json:"articles"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	Total      int             `json:"total"`
+}
@@ -22,7 +22,7 @@ import (
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
-func SetupRouter(userHandler *UserHandler, articleHandler *ArticleHandler, ratingHandler *RatingHandler, recommendHandler *RecommendHandler) *gin.Engine {
+func SetupRouter(userHandler *UserHandler, articleHandler *ArticleHandler, ratingHandler *RatingHandler, tagHandler *TagHandler, collectionHandler *CollectionHandler, recommendHandler *RecommendHandler, rescrapeLimiter gin.HandlerFunc) *gin.Engine {
 	// gin.ReleaseMode or gin.DebugMode
 	gin.SetMode(gin.ReleaseMode)
 
@@ -51,7 +51,11 @@ func SetupRouter(userHandler *UserHandler, articleHandler *ArticleHandler, ratin
 	// 路由分組
 	r.POST("/signup", userHandler.Signup)
 	r.POST("/login", userHandler.Login)
+	r.POST("/auth/refresh", userHandler.Refresh)
+	r.GET("/.well-known/jwks.json", userHandler.JWKS)
 	r.GET("/me", middleware.AuthMiddleware(userHandler.AuthService), userHandler.Me)
+	r.POST("/logout", middleware.AuthMiddleware(userHandler.AuthService), userHandler.Logout)
+	r.POST("/logout-all", middleware.AuthMiddleware(userHandler.AuthService), userHandler.LogoutAll)
 
 	apiV1 := r.Group("/api/v1")
 	apiV1.Use(middleware.AuthMiddleware(userHandler.AuthService))
@@ -59,12 +63,38 @@ func SetupRouter(userHandler *UserHandler, articleHandler *ArticleHandler, ratin
 		// 文章收藏 API
 		apiV1.POST("/articles", articleHandler.PostArticle)
 		apiV1.GET("/articles", articleHandler.GetArticles)
+		apiV1.GET("/articles/search", articleHandler.SearchArticles)
+		apiV1.PATCH("/articles/:id", articleHandler.PatchArticle)
+		apiV1.PATCH("/articles/:id/state", articleHandler.PatchArticleState)
+		apiV1.POST("/articles/state", articleHandler.BulkUpdateArticleState)
 		apiV1.DELETE("/articles/:id", articleHandler.DeleteArticle)
+		apiV1.GET("/articles/:id/scrape-attempts", articleHandler.GetScrapeAttempts)
+		apiV1.GET("/articles/:id/content", articleHandler.GetArticleContent)
+		apiV1.GET("/articles/:id/image", articleHandler.GetArticleImage)
+		apiV1.POST("/articles/:id/rescrape", rescrapeLimiter, articleHandler.RescrapeArticle)
+		apiV1.POST("/articles/rescrape", rescrapeLimiter, articleHandler.BulkRescrapeArticles)
 
 		apiV1.POST("/articles/:id/rate", ratingHandler.RateArticle)
 		apiV1.GET("/articles/:id/rate", ratingHandler.GetRating)
 		apiV1.DELETE("/articles/:id/rate", ratingHandler.DeleteRating)
 
+		apiV1.GET("/articles/:id/tags", tagHandler.GetArticleTags)
+		apiV1.POST("/articles/:id/tags", tagHandler.AddArticleTags)
+		apiV1.DELETE("/articles/:id/tags/:tag_id", tagHandler.RemoveArticleTag)
+		apiV1.GET("/tags", tagHandler.GetTags)
+		apiV1.PATCH("/tags/:id", tagHandler.RenameTag)
+		apiV1.DELETE("/tags/:id", tagHandler.DeleteTag)
+
+		apiV1.GET("/collections", collectionHandler.GetCollections)
+		apiV1.POST("/collections", collectionHandler.PostCollection)
+		apiV1.GET("/collections/:id", collectionHandler.GetCollection)
+		apiV1.PATCH("/collections/:id", collectionHandler.PatchCollection)
+		apiV1.DELETE("/collections/:id", collectionHandler.DeleteCollection)
+		apiV1.GET("/collections/:id/articles", collectionHandler.GetCollectionArticles)
+		apiV1.POST("/collections/:id/articles", collectionHandler.PostCollectionArticle)
+		apiV1.PATCH("/collections/:id/articles/:article_id", collectionHandler.MoveCollectionArticle)
+		apiV1.DELETE("/collections/:id/articles/:article_id", collectionHandler.DeleteCollectionArticle)
+
 		apiV1.GET("/recommendations", recommendHandler.GetRecommendations)
 	}
 
@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"deeliai/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TagHandler struct {
+	tagService *service.TagService
+}
+
+func NewTagHandler(s *service.TagService) *TagHandler {
+	return &TagHandler{tagService: s}
+}
+
+// @Summary 獲取標籤列表
+// @Description 列出使用者所有標籤與各自的文章數，依文章數由多到少排序
+// @Tags tags
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Produce json
+// @Success 200 {object} StandardResponse{data=[]model.TagCount} "成功獲取標籤列表"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /tags [get]
+func (h *TagHandler) GetTags(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	tags, err := h.tagService.ListTags(c.Request.Context(), emailAny.(string))
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Get success", tags)
+}
+
+// @Summary 重新命名標籤
+// @Description 修改標籤名稱，所有使用該標籤的文章都會跟著改變；名稱會去除前後空白、合併連續空白並轉為小寫，與既有標籤相同時兩者合併
+// @Tags tags
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Accept json
+// @Produce json
+// @Param id path string true "標籤 ID"
+// @Param request body RenameTagRequest true "新的標籤名稱"
+// @Success 200 {object} StandardResponse{data=model.Tag} "修改成功"
+// @Failure 400 {object} ErrorResponse "無效的標籤 ID 或名稱"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "標籤不存在"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /tags/{id} [patch]
+func (h *TagHandler) RenameTag(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	tagUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid tag id")
+		return
+	}
+
+	var req RenameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	tag, err := h.tagService.RenameTag(c.Request.Context(), emailAny.(string), tagUUID, req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTag):
+			RespondWithError(c, http.StatusBadRequest, err, "Invalid tag name")
+		case errors.Is(err, sql.ErrNoRows):
+			RespondWithError(c, http.StatusNotFound, err, "Tag not found")
+		default:
+			RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		}
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Update success", tag)
+}
+
+// @Summary 刪除標籤
+// @Description 刪除標籤並從所有文章移除
+// @Tags tags
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Param id path string true "標籤 ID"
+// @Success 200 {object} StandardResponse "刪除成功"
+// @Failure 400 {object} ErrorResponse "無效的標籤 ID"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "標籤不存在"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	tagUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid tag id")
+		return
+	}
+
+	err = h.tagService.DeleteTag(c.Request.Context(), emailAny.(string), tagUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondWithError(c, http.StatusNotFound, err, "Tag not found")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Delete success", nil)
+}
+
+// @Summary 獲取文章的標籤
+// @Description 列出文章的標籤，依名稱排序
+// @Tags tags
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Produce json
+// @Param id path string true "文章 ID"
+// @Success 200 {object} StandardResponse{data=[]model.Tag} "成功獲取標籤"
+// @Failure 400 {object} ErrorResponse "無效的文章 ID"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "文章不存在"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /articles/{id}/tags [get]
+func (h *TagHandler) GetArticleTags(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	articleUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid article id")
+		return
+	}
+
+	tags, err := h.tagService.ListArticleTags(c.Request.Context(), articleUUID, emailAny.(string))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondWithError(c, http.StatusNotFound, err, "Article not found")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Get success", tags)
+}
+
+// @Summary 為文章加上標籤
+// @Description 為文章加上標籤，不需要先評分；尚不存在的標籤會自動建立，名稱會去除前後空白、合併連續空白並轉為小寫
+// @Tags tags
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Accept json
+// @Produce json
+// @Param id path string true "文章 ID"
+// @Param request body AddArticleTagsRequest true "標籤名稱"
+// @Success 200 {object} StandardResponse{data=[]model.Tag} "文章加上後的所有標籤"
+// @Failure 400 {object} ErrorResponse "無效的文章 ID 或標籤名稱"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "文章不存在"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /articles/{id}/tags [post]
+func (h *TagHandler) AddArticleTags(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	articleUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid article id")
+		return
+	}
+
+	var req AddArticleTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	tags, err := h.tagService.AddArticleTags(c.Request.Context(), articleUUID, emailAny.(string), req.Tags)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTag):
+			RespondWithError(c, http.StatusBadRequest, err, "Invalid tag name")
+		case errors.Is(err, sql.ErrNoRows):
+			RespondWithError(c, http.StatusNotFound, err, "Article not found")
+		default:
+			RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		}
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Update success", tags)
+}
+
+// @Summary 移除文章的標籤
+// @Description 從文章移除標籤，標籤本身保留
+// @Tags tags
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Param id path string true "文章 ID"
+// @Param tag_id path string true "標籤 ID"
+// @Success 200 {object} StandardResponse "移除成功"
+// @Failure 400 {object} ErrorResponse "無效的文章或標籤 ID"
+// @Failure 401 {object} ErrorResponse "未授權"
+// @Failure 404 {object} ErrorResponse "文章不存在或沒有此標籤"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /articles/{id}/tags/{tag_id} [delete]
+func (h *TagHandler) RemoveArticleTag(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	articleUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid article id")
+		return
+	}
+
+	tagUUID, err := uuid.Parse(c.Param("tag_id"))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid tag id")
+		return
+	}
+
+	err = h.tagService.RemoveArticleTag(c.Request.Context(), articleUUID, tagUUID, emailAny.(string))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RespondWithError(c, http.StatusNotFound, err, "Article or tag not found")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Delete success", nil)
+}
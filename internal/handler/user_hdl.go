@@ -62,7 +62,7 @@ type LoginRequest struct {
 // @Accept json
 // @Produce json
 // @Param request body LoginRequest true "登入請求"
-// @Success 200 {object} StandardResponse{data=object{token=string}}
+// @Success 200 {object} StandardResponse{data=model.TokenPair}
 // @Failure 400 {object} ErrorResponse "無效的請求"
 // @Failure 401 {object} ErrorResponse "憑證無效"
 // @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
@@ -81,13 +81,95 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.AuthService.GenerateToken(user.Email)
+	tokens, err := h.AuthService.Login(c.Request.Context(), user.Email, c.Request.UserAgent(), c.ClientIP())
 	if err != nil {
 		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
 		return
 	}
 
-	RespondWithSuccess(c, http.StatusOK, "Login success", gin.H{"token": token})
+	RespondWithSuccess(c, http.StatusOK, "Login success", tokens)
+}
+
+// @Summary 刷新 Token
+// @Description 以 refresh token 換發新的 access token，舊的 refresh token 會立即失效
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body RefreshTokenRequest true "刷新請求"
+// @Success 200 {object} StandardResponse{data=model.TokenPair}
+// @Failure 400 {object} ErrorResponse "無效的請求"
+// @Failure 401 {object} ErrorResponse "refresh token 無效、過期或已被撤銷"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /auth/refresh [post]
+func (h *UserHandler) Refresh(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, err, "Invalid request body")
+		return
+	}
+
+	tokens, err := h.AuthService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) || errors.Is(err, service.ErrSessionRevoked) {
+			RespondWithError(c, http.StatusUnauthorized, err, "Invalid refresh token")
+			return
+		}
+
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Refresh success", tokens)
+}
+
+// @Summary 登出
+// @Description 撤銷目前裝置的 Session，對應的 access token 與 refresh token 皆會失效
+// @Tags users
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Produce json
+// @Success 200 {object} StandardResponse "登出成功"
+// @Failure 401 {object} ErrorResponse "未授權，JWT 驗證失敗"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /logout [post]
+func (h *UserHandler) Logout(c *gin.Context) {
+	sessionIDAny, exists := c.Get("session_id")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	if err := h.AuthService.Logout(c.Request.Context(), sessionIDAny.(string)); err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Logout success", nil)
+}
+
+// @Summary 登出所有裝置
+// @Description 撤銷使用者所有的 Session
+// @Tags users
+// @Security BearerAuth
+// @Param Authorization header string true "JWT token" default(Bearer <your_JWT_token>)
+// @Produce json
+// @Success 200 {object} StandardResponse "登出成功"
+// @Failure 401 {object} ErrorResponse "未授權，JWT 驗證失敗"
+// @Failure 500 {object} ErrorResponse "內部伺服器錯誤"
+// @Router /logout-all [post]
+func (h *UserHandler) LogoutAll(c *gin.Context) {
+	emailAny, exists := c.Get("email")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, errors.New(""), "User not authenticated")
+		return
+	}
+
+	if err := h.AuthService.LogoutAll(c.Request.Context(), emailAny.(string)); err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err, "Something went wrong")
+		return
+	}
+
+	RespondWithSuccess(c, http.StatusOK, "Logout success", nil)
 }
 
 // @Summary 獲取使用者個人資料
@@ -117,3 +199,15 @@ func (h *UserHandler) Me(c *gin.Context) {
 
 	RespondWithSuccess(c, http.StatusOK, "Login success", user)
 }
+
+// @Summary 取得 JWT 驗證公鑰
+// @Description 以 JWKS (RFC 7517) 格式公開目前有效的驗證公鑰，供其他服務驗證 token
+// @Tags users
+// @Produce json
+// @Success 200 {object} service.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *UserHandler) JWKS(c *gin.Context) {
+	// JWKS 需符合標準格式，因此不使用 StandardResponse 包裝
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.AuthService.JWKS())
+}
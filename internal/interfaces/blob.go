@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrBlobNotFound 表示 key 對應的物件不存在
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore 是存放二進位檔案 (例如預覽圖縮圖) 的抽象介面，可替換為本機檔案系統或物件儲存
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Get 回傳的 ReadCloser 需由呼叫端關閉，物件不存在時回傳 ErrBlobNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import "context"
+
+// LeaderLock 是跨程序的互斥鎖，用來確保多個實例中只有一個執行特定工作 (例如排程器)
+type LeaderLock interface {
+	// TryAcquire 嘗試取得鎖，不會阻塞；回傳是否取得
+	TryAcquire(ctx context.Context) (bool, error)
+	// Check 確認鎖仍然持有 (例如底層連線未中斷)
+	Check(ctx context.Context) error
+	// Release 釋放鎖
+	Release(ctx context.Context) error
+}
@@ -13,4 +13,6 @@ type QueueConsumer interface {
 	Start()
 	// Produce 方法接受一個任務字串，並將其發布
 	Consume()
+	// Stop 通知 worker 停止取新任務，並等待處理中的任務結束
+	Stop()
 }
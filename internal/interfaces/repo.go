@@ -3,6 +3,8 @@ package interfaces
 import (
 	"context"
 	"deeliai/internal/model"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,18 +16,48 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
 }
 
+// ErrArticleExists 表示使用者已收藏過正規化後相同的網址
+var ErrArticleExists = errors.New("article already exists")
+
 type ArticleRepository interface {
 	Create(ctx context.Context, article *model.Article) (*model.Article, error)
-	UpdateMetadata(ctx context.Context, articleID uuid.UUID, title, description, imageURL string) error
-	MarkScrapeFailed(ctx context.Context, articleID uuid.UUID) error
-	ListByUserEmail(ctx context.Context, userEmail string, limit, offset int) ([]model.Article, error)
-	FindByID(ctx context.Context, articleID uuid.UUID) (*model.Article, error)
+	ListByUserEmail(ctx context.Context, userEmail string, query model.ArticleQuery) ([]model.Article, *model.ArticleCursor, error)
+	CountByUserEmail(ctx context.Context, userEmail string, filter model.ArticleFilter) (int, error)
+	Search(ctx context.Context, userEmail, query string, limit, offset int) ([]model.ArticleSearchResult, error)
 	FindByIDAndUserEmail(ctx context.Context, articleID uuid.UUID, userEmail string) (*model.Article, error)
+	FindByCanonicalURL(ctx context.Context, userEmail, canonicalURL string) (*model.Article, error)
+	Update(ctx context.Context, articleID uuid.UUID, userEmail string, update model.ArticleUpdate) (*model.Article, error)
+	UpdateState(ctx context.Context, userEmail string, articleIDs []uuid.UUID, update model.ArticleStateUpdate) (int, error)
 	Delete(ctx context.Context, articleID uuid.UUID, userEmail string) error
-	FindFailedScrapes(ctx context.Context) ([]model.Article, error)
+}
+
+// PageRepository 存取以正規化網址為 key 的共用爬取結果，爬取狀態與重試都以頁面為單位
+type PageRepository interface {
+	FindOrCreate(ctx context.Context, canonicalURL, url string) (page *model.Page, created bool, err error)
+	FindByID(ctx context.Context, pageID uuid.UUID) (*model.Page, error)
+	UpdateMetadata(ctx context.Context, pageID uuid.UUID, meta *model.ArticleMetadata) error
+	SetThumbnailKey(ctx context.Context, pageID uuid.UUID, key string) error
+	MarkScrapeFailed(ctx context.Context, pageID uuid.UUID, nextAttemptAt time.Time, lastError string) error
+	MarkScrapeFailedPermanently(ctx context.Context, pageID uuid.UUID, lastError string) error
+	MarkScrapeDeferred(ctx context.Context, pageID uuid.UUID, nextAttemptAt time.Time) error
+	ClaimDueScrapes(ctx context.Context, limit int) ([]uuid.UUID, error)
+	ResetScrape(ctx context.Context, pageID uuid.UUID) error
+	ResetIfStale(ctx context.Context, pageID uuid.UUID, staleBefore time.Time) (bool, error)
+	ResetStaleScrapes(ctx context.Context, staleBefore time.Time, limit int) ([]uuid.UUID, error)
+	ResetScrapesByFilter(ctx context.Context, userEmail string, filter model.RescrapeFilter, limit int) ([]uuid.UUID, error)
+
+	ListRecommendPages(ctx context.Context, userEmail string) ([]model.Page, error)
+	FindLatestPages(ctx context.Context, userEmail string, limit int) ([]model.Page, error)
+}
 
-	ListRecommendArticles(ctx context.Context, userEmail string) ([]model.Article, error)
-	FindLatestArticles(ctx context.Context, userEmail string, limit int) ([]model.Article, error)
+type PageContentRepository interface {
+	Upsert(ctx context.Context, content *model.PageContent) error
+	FindByPageID(ctx context.Context, pageID uuid.UUID) (*model.PageContent, error)
+}
+
+type ScrapeAttemptRepository interface {
+	Create(ctx context.Context, attempt *model.ScrapeAttempt) error
+	ListByPageID(ctx context.Context, pageID uuid.UUID, limit int) ([]model.ScrapeAttempt, error)
 }
 
 type RatingRepository interface {
@@ -33,3 +65,47 @@ type RatingRepository interface {
 	FindRatingByUserEmailAndArticleID(ctx context.Context, userEmail string, articleID uuid.UUID) (*model.Rating, error)
 	Delete(ctx context.Context, userEmail string, articleID uuid.UUID) error
 }
+
+// TagRepository 存取使用者的標籤與文章的標籤關聯，傳入的名稱需已正規化且不重複
+type TagRepository interface {
+	ListByUserEmail(ctx context.Context, userEmail string) ([]model.TagCount, error)
+	ListByArticleID(ctx context.Context, articleID uuid.UUID) ([]model.Tag, error)
+	AddToArticle(ctx context.Context, userEmail string, articleID uuid.UUID, names []string) error
+	SetArticleTags(ctx context.Context, userEmail string, articleID uuid.UUID, names []string) error
+	RemoveFromArticle(ctx context.Context, articleID, tagID uuid.UUID) error
+	Rename(ctx context.Context, userEmail string, tagID uuid.UUID, name string) (*model.Tag, error)
+	Delete(ctx context.Context, userEmail string, tagID uuid.UUID) error
+}
+
+// ErrCollectionExists 表示使用者已有同名的收藏夾
+var ErrCollectionExists = errors.New("collection already exists")
+
+// CollectionRepository 存取使用者的收藏夾與其中的文章，呼叫前需先確認收藏夾與文章都屬於同一位使用者
+type CollectionRepository interface {
+	Create(ctx context.Context, collection *model.Collection) (*model.Collection, error)
+	FindOrCreateDefault(ctx context.Context, userEmail string) (*model.Collection, error)
+	ListByUserEmail(ctx context.Context, userEmail string) ([]model.Collection, error)
+	FindByIDAndUserEmail(ctx context.Context, collectionID uuid.UUID, userEmail string) (*model.Collection, error)
+	Update(ctx context.Context, collectionID uuid.UUID, userEmail string, update model.CollectionUpdate) (*model.Collection, error)
+	Delete(ctx context.Context, collectionID uuid.UUID, userEmail string) error
+	AddArticle(ctx context.Context, collectionID, articleID uuid.UUID) error
+	RemoveArticle(ctx context.Context, collectionID, articleID uuid.UUID) error
+	MoveArticle(ctx context.Context, collectionID, articleID uuid.UUID, position int) error
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *model.Session) (*model.Session, error)
+	FindByID(ctx context.Context, sessionID uuid.UUID) (*model.Session, error)
+	RotateRefreshToken(ctx context.Context, sessionID uuid.UUID, oldHash, newHash string) (*model.Session, error)
+	Revoke(ctx context.Context, sessionID uuid.UUID) error
+	RevokeAllByUserEmail(ctx context.Context, userEmail string) error
+}
+
+// ErrScrapeJobLeaseLost 表示任務在 Ack 前已超過 visibility timeout 並被其他 worker 重新取出
+var ErrScrapeJobLeaseLost = errors.New("scrape job lease lost")
+
+type ScrapeJobRepository interface {
+	Enqueue(ctx context.Context, payload string) error
+	Dequeue(ctx context.Context, workerID string, limit int, visibilityTimeout time.Duration) ([]model.ScrapeJob, error)
+	Ack(ctx context.Context, job *model.ScrapeJob) error
+}
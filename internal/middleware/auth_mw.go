@@ -33,8 +33,16 @@ func AuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
 			return
 		}
 
+		// 確認 token 所屬的 Session 沒有被登出或撤銷
+		if err := authService.ValidateSession(c.Request.Context(), claims); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session revoked"})
+			c.Abort()
+			return
+		}
+
 		// 將使用者 ID 存入 Gin context，以便後續的 handler 使用
 		c.Set("email", claims.Email)
+		c.Set("session_id", claims.SessionID)
 		c.Next()
 	}
 }
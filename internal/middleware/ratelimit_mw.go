@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// userLimiter 記錄單一使用者的 token bucket 與最後使用時間
+type userLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimitByUser 以 token bucket 限制每位使用者的請求頻率，需放在 AuthMiddleware 之後
+// perMinute 為每分鐘補充的請求數，burst 為可累積的最大請求數
+func RateLimitByUser(perMinute float64, burst int) gin.HandlerFunc {
+	var mu sync.Mutex
+	limiters := make(map[string]*userLimiter)
+	lastCleanup := time.Now()
+
+	return func(c *gin.Context) {
+		email := c.GetString("email")
+		now := time.Now()
+
+		mu.Lock()
+		// 定期清除閒置的使用者，避免 map 無限成長
+		if now.Sub(lastCleanup) > 10*time.Minute {
+			for key, l := range limiters {
+				if now.Sub(l.lastSeen) > 10*time.Minute {
+					delete(limiters, key)
+				}
+			}
+			lastCleanup = now
+		}
+
+		l, ok := limiters[email]
+		if !ok {
+			l = &userLimiter{limiter: rate.NewLimiter(rate.Limit(perMinute/60), burst)}
+			limiters[email] = l
+		}
+		l.lastSeen = now
+		allowed := l.limiter.AllowN(now, 1)
+		mu.Unlock()
+
+		if !allowed {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
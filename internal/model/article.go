@@ -1,20 +1,158 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// Article 是使用者收藏的文章，只記錄使用者與頁面的關聯；爬取到的 metadata 屬於 Page，由所有收藏同一網址的使用者共用
+// 查詢時會與 pages 合併，讓 API 回傳的欄位與以往相同
 type Article struct {
-	ID           uuid.UUID `db:"id" json:"id"`
-	UserEmail    string    `db:"user_email" json:"user_email"`
-	URL          string    `db:"url" json:"url"`
-	Title        *string   `db:"title" json:"title,omitempty"`
-	Description  *string   `db:"description" json:"description,omitempty"`
-	ImageURL     *string   `db:"image_url" json:"image_url,omitempty"`
-	ScrapeStatus string    `db:"scrape_status" json:"scrape_status"`
-	RetryCount   int       `db:"retry_count" json:"-"` // 不顯示給使用者
-	CreatedAt    time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	ID                uuid.UUID      `db:"id" json:"id"`
+	UserEmail         string         `db:"user_email" json:"user_email"`
+	PageID            uuid.UUID      `db:"page_id" json:"page_id"`
+	URL               string         `db:"url" json:"url"`                               // 使用者提交的原始網址
+	CanonicalURL      *string        `db:"canonical_url" json:"canonical_url,omitempty"` // 正規化後的網址，用於偵測重複收藏
+	Title             *string        `db:"title" json:"title,omitempty"`                 // 有 custom_title 時為 custom_title，否則為爬取到的標題
+	Description       *string        `db:"description" json:"description,omitempty"`     // 有 custom_description 時為 custom_description，否則為爬取到的描述
+	CustomTitle       *string        `db:"custom_title" json:"custom_title,omitempty"`
+	CustomDescription *string        `db:"custom_description" json:"custom_description,omitempty"`
+	Notes             *string        `db:"notes" json:"notes,omitempty"`
+	Tags              pq.StringArray `db:"tags" json:"tags" swaggertype:"array,string"` // 使用者加上的標籤，依名稱排序
+	ReadAt            *time.Time     `db:"read_at" json:"read_at,omitempty"`            // 第一次標記為已讀的時間，未讀為 nil
+	ArchivedAt        *time.Time     `db:"archived_at" json:"archived_at,omitempty"`    // 封存的時間，未封存為 nil
+	Favorited         bool           `db:"favorited" json:"favorited"`
+	ImageURL          *string        `db:"image_url" json:"image_url,omitempty"`
+	ScrapeStatus      string         `db:"scrape_status" json:"scrape_status"`
+	NextAttemptAt     *time.Time     `db:"next_attempt_at" json:"next_attempt_at,omitempty"`
+	LastError         *string        `db:"last_error" json:"last_error,omitempty"` // 最近一次爬取失敗的原因
+	Author            *string        `db:"author" json:"author,omitempty"`
+	SiteName          *string        `db:"site_name" json:"site_name,omitempty"`
+	FaviconURL        *string        `db:"favicon_url" json:"favicon_url,omitempty"`
+	CanonicalLink     *string        `db:"canonical_link" json:"canonical_link,omitempty"` // 頁面宣告的 canonical 網址
+	OEmbedURL         *string        `db:"oembed_url" json:"oembed_url,omitempty"`         // oEmbed 端點，供前端嵌入內容
+	Language          *string        `db:"language" json:"language,omitempty"`
+	Keywords          pq.StringArray `db:"keywords" json:"keywords,omitempty" swaggertype:"array,string"`
+	PublishedAt       *time.Time     `db:"published_at" json:"published_at,omitempty"`
+	ModifiedAt        *time.Time     `db:"modified_at" json:"modified_at,omitempty"`
+	DocumentType      *string        `db:"document_type" json:"document_type,omitempty"`        // html、pdf、image 或 text
+	Extras            Extras         `db:"extras" json:"extras,omitempty" swaggertype:"object"` // 網站特有的資訊，例如影片長度、repo 星數
+	ThumbnailKey      *string        `db:"thumbnail_key" json:"-"`                              // 快取縮圖在 BlobStore 中的 key，由 GET /articles/:id/image 提供
+	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"` // 文章或頁面最後更新的時間
+}
+
+// 文章列表的排序欄位
+const (
+	ArticleSortCreatedAt = "created_at"
+	ArticleSortRating    = "rating"
+	ArticleSortTitle     = "title"
+	ArticleSortPosition  = "position" // 收藏夾中的順序，只能與 ArticleFilter.CollectionID 一起使用
+)
+
+// ArticleFilter 是文章列表的篩選條件，零值代表不限制
+type ArticleFilter struct {
+	Status        string     // 爬取狀態
+	Domain        string     // 網域，包含子網域
+	Tag           string     // 使用者加上的標籤
+	MinRating     int        // 評分下限，設定時不包含未評分的文章
+	MaxRating     int        // 評分上限，設定時不包含未評分的文章
+	CreatedAfter  *time.Time // 收藏時間不早於此時間
+	CreatedBefore *time.Time // 收藏時間早於此時間
+	CollectionID  *uuid.UUID // 只列出此收藏夾中的文章
+	Read          *bool      // 是否已讀
+	Archived      *bool      // 是否已封存
+	Favorited     *bool      // 是否加入最愛
+}
+
+// ArticleCursor 是 keyset 分頁的位置：上一頁最後一篇文章的排序值與 ID
+type ArticleCursor struct {
+	SortKey string
+	ID      uuid.UUID
+}
+
+// ArticleQuery 是查詢文章列表的條件
+type ArticleQuery struct {
+	Filter ArticleFilter
+	Sort   string         // ArticleSort* 其中之一
+	Desc   bool           // 是否遞減排序
+	After  *ArticleCursor // 從這個位置之後開始，nil 代表第一頁
+	Limit  int
+}
+
+// ArticleSearchResult 是全文搜尋的結果，Snippet 中符合的字詞以 <mark></mark> 標示，其餘內容已經過 HTML escape
+type ArticleSearchResult struct {
+	Article
+	Rank    float64 `db:"rank" json:"rank"`
+	Snippet string  `db:"snippet" json:"snippet"`
+}
+
+// RescrapeFilter 是批次重新爬取時篩選文章的條件，空值代表不限制
+type RescrapeFilter struct {
+	Status    string     // 爬取狀態
+	Domain    string     // 網域，包含子網域
+	OlderThan *time.Time // 最後更新時間早於此時間
+}
+
+// ArticleUpdate 是使用者可修改的文章欄位，nil 代表不修改，空字串代表清除自訂值、改用爬取到的值
+type ArticleUpdate struct {
+	CustomTitle       *string
+	CustomDescription *string
+	Notes             *string
+}
+
+// ArticleStateUpdate 是文章的閱讀狀態，nil 代表不修改
+type ArticleStateUpdate struct {
+	Read      *bool
+	Archived  *bool
+	Favorited *bool
+}
+
+// ArticleMetadata 是從網頁爬取到的 metadata，空字串代表該欄位沒有找到
+type ArticleMetadata struct {
+	Title         string
+	Description   string
+	ImageURL      string
+	Author        string
+	SiteName      string
+	FaviconURL    string
+	CanonicalLink string
+	CanonicalURL  string // 正規化後的 CanonicalLink，由 ScrapeService 填入
+	OEmbedURL     string
+	Language      string
+	Keywords      []string
+	PublishedAt   *time.Time
+	ModifiedAt    *time.Time
+	DocumentType  string
+	Extras        Extras
+}
+
+// Extras 是存在 JSONB 欄位中的附加資訊
+type Extras map[string]any
+
+// Value 將 Extras 寫入 JSONB，空值存為 NULL
+func (e Extras) Value() (driver.Value, error) {
+	if len(e) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan 從 JSONB 讀出 Extras
+func (e *Extras) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*e = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, e)
+	case string:
+		return json.Unmarshal([]byte(v), e)
+	}
+	return fmt.Errorf("cannot scan %T into Extras", src)
 }
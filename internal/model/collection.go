@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Collection 是使用者整理文章用的收藏夾，一篇文章可以放在多個收藏夾中
+// 每位使用者有一個預設的 Inbox，新收藏的文章會自動放進去
+type Collection struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	UserEmail    string    `db:"user_email" json:"user_email"`
+	Name         string    `db:"name" json:"name"`
+	Description  *string   `db:"description" json:"description,omitempty"`
+	IsDefault    bool      `db:"is_default" json:"is_default"`
+	ArticleCount int       `db:"article_count" json:"article_count"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// DefaultCollectionName 是預設收藏夾的名稱
+const DefaultCollectionName = "Inbox"
+
+// CollectionUpdate 是可修改的收藏夾欄位，nil 代表不修改，Description 為空字串代表清除
+type CollectionUpdate struct {
+	Name        *string
+	Description *string
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// 頁面的爬取狀態
+const (
+	ScrapeStatusPending         = "pending"
+	ScrapeStatusSuccess         = "success"
+	ScrapeStatusFailed          = "failed"           // 失敗，等待 next_attempt_at 到期後重試
+	ScrapeStatusFailedPermanent = "failed_permanent" // 無法挽回的錯誤 (例如 404) 或已用完重試次數
+	ScrapeStatusDeferred        = "deferred"         // 目標主機忙碌，延後到 next_attempt_at 再爬取，不計入重試次數
+)
+
+// 頁面網址指向的文件類型
+const (
+	DocumentTypeHTML  = "html"
+	DocumentTypePDF   = "pdf"
+	DocumentTypeImage = "image"
+	DocumentTypeText  = "text"
+)
+
+// Page 是以正規化網址為 key 的爬取結果，同一網址不論被多少使用者收藏都只爬取一次
+type Page struct {
+	ID                  uuid.UUID      `db:"id" json:"id"`
+	CanonicalURL        string         `db:"canonical_url" json:"canonical_url"`
+	URL                 string         `db:"url" json:"url"` // 實際爬取的網址，取第一位使用者提交的原始網址
+	Title               *string        `db:"title" json:"title,omitempty"`
+	Description         *string        `db:"description" json:"description,omitempty"`
+	ImageURL            *string        `db:"image_url" json:"image_url,omitempty"`
+	Author              *string        `db:"author" json:"author,omitempty"`
+	SiteName            *string        `db:"site_name" json:"site_name,omitempty"`
+	FaviconURL          *string        `db:"favicon_url" json:"favicon_url,omitempty"`
+	CanonicalLink       *string        `db:"canonical_link" json:"canonical_link,omitempty"`
+	ScrapedCanonicalURL *string        `db:"scraped_canonical_url" json:"-"` // 正規化後的 CanonicalLink
+	OEmbedURL           *string        `db:"oembed_url" json:"oembed_url,omitempty"`
+	Language            *string        `db:"language" json:"language,omitempty"`
+	Keywords            pq.StringArray `db:"keywords" json:"keywords,omitempty" swaggertype:"array,string"`
+	PublishedAt         *time.Time     `db:"published_at" json:"published_at,omitempty"`
+	ModifiedAt          *time.Time     `db:"modified_at" json:"modified_at,omitempty"`
+	DocumentType        *string        `db:"document_type" json:"document_type,omitempty"`
+	Extras              Extras         `db:"extras" json:"extras,omitempty" swaggertype:"object"`
+	ThumbnailKey        *string        `db:"thumbnail_key" json:"-"`
+	ScrapeStatus        string         `db:"scrape_status" json:"scrape_status"`
+	RetryCount          int            `db:"retry_count" json:"-"` // 不顯示給使用者
+	NextAttemptAt       *time.Time     `db:"next_attempt_at" json:"next_attempt_at,omitempty"`
+	LastError           *string        `db:"last_error" json:"last_error,omitempty"`
+	ScrapedAt           *time.Time     `db:"scraped_at" json:"scraped_at,omitempty"` // 最近一次爬取成功的時間
+	CreatedAt           time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time      `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PageContent 是從網頁擷取出的文章主體，HTML 已經過 sanitize，可直接用於離線閱讀
+type PageContent struct {
+	PageID             uuid.UUID `db:"page_id" json:"page_id"`
+	ContentHTML        string    `db:"content_html" json:"content_html"`
+	ContentText        string    `db:"content_text" json:"content_text"`
+	WordCount          int       `db:"word_count" json:"word_count"`
+	ReadingTimeMinutes int       `db:"reading_time_minutes" json:"reading_time_minutes"`
+	ExtractedAt        time.Time `db:"extracted_at" json:"extracted_at"`
+}
@@ -11,7 +11,7 @@ type Rating struct {
 	UserEmail string    `db:"user_email" json:"user_email"`
 	ArticleID uuid.UUID `db:"article_id" json:"article_id"`
 	Scores    int       `db:"scores" json:"scores"`
-	Tags      []string  `db:"tags" json:"tags"`
+	Tags      []string  `db:"tags" json:"tags"` // 文章的標籤，存在 article_tags，與評分分開管理
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScrapeAttempt 記錄頁面單次爬取的結果，方便追查文章為何沒有 metadata
+type ScrapeAttempt struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	PageID       uuid.UUID `db:"page_id" json:"page_id"`
+	Success      bool      `db:"success" json:"success"`
+	HTTPStatus   *int      `db:"http_status" json:"http_status,omitempty"`
+	ErrorClass   *string   `db:"error_class" json:"error_class,omitempty"`
+	ErrorMessage *string   `db:"error_message" json:"error_message,omitempty"`
+	DurationMS   int       `db:"duration_ms" json:"duration_ms"`
+	FinalURL     *string   `db:"final_url" json:"final_url,omitempty"` // 跟隨轉址後實際抓取的 URL
+	AttemptedAt  time.Time `db:"attempted_at" json:"attempted_at"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// ScrapeJob 是持久化佇列中的一筆爬取任務
+type ScrapeJob struct {
+	ID         int64     `db:"id" json:"id"`
+	Payload    string    `db:"payload" json:"payload"`
+	Attempts   int       `db:"attempts" json:"attempts"`     // 被取出處理的次數
+	Generation int       `db:"generation" json:"generation"` // 任務處理中又被排入時加一，Ack 時據此判斷是否需要再處理一次
+	VisibleAt  time.Time `db:"visible_at" json:"visible_at"` // 在此時間之前不會被其他 worker 取出
+	LockedBy   string    `db:"locked_by" json:"locked_by"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
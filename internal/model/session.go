@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Session 代表一次登入 (一個裝置)，refresh token 輪替時沿用同一個 Session
+type Session struct {
+	ID                         uuid.UUID      `db:"id" json:"id"`
+	UserEmail                  string         `db:"user_email" json:"user_email"`
+	RefreshTokenHash           string         `db:"refresh_token_hash" json:"-"`
+	PreviousRefreshTokenHashes pq.StringArray `db:"previous_refresh_token_hashes" json:"-"` // 已被輪替掉的雜湊，新的在前
+	UserAgent                  string         `db:"user_agent" json:"user_agent"`
+	IPAddress                  string         `db:"ip_address" json:"ip_address"`
+	ExpiresAt                  time.Time      `db:"expires_at" json:"expires_at"`
+	RevokedAt                  *time.Time     `db:"revoked_at" json:"revoked_at,omitempty"`
+	LastUsedAt                 time.Time      `db:"last_used_at" json:"last_used_at"`
+	CreatedAt                  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt                  time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// TokenPair 是登入或刷新後回傳給使用者的 token 組合
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // access token 剩餘秒數
+}
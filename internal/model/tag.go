@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tag 是使用者自己的標籤，名稱已正規化 (去除前後空白、連續空白合併為一個、轉小寫)
+type Tag struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	UserEmail string    `db:"user_email" json:"user_email"`
+	Name      string    `db:"name" json:"name"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// TagCount 是標籤與使用該標籤的文章數
+type TagCount struct {
+	Tag
+	ArticleCount int `db:"article_count" json:"article_count"`
+}
@@ -5,14 +5,20 @@ import (
 	"deeliai/internal/interfaces"
 	"deeliai/internal/service"
 	"log"
+	"sync"
 	"time"
 )
 
+// taskTimeout 是單一爬取任務的最長執行時間
+const taskTimeout = 30 * time.Second
+
 // channelConsumer 是 QueueConsumer 介面基於 Go Channel 的實現
 type channelConsumer struct {
 	queue         chan string
 	scrapeService *service.ScrapeService
 	workerCount   int
+	done          chan struct{}
+	wg            sync.WaitGroup
 }
 
 func NewChannelConsumer(q chan string, s *service.ScrapeService, cnt int) interfaces.QueueConsumer {
@@ -20,6 +26,7 @@ func NewChannelConsumer(q chan string, s *service.ScrapeService, cnt int) interf
 		queue:         q,
 		scrapeService: s,
 		workerCount:   cnt,
+		done:          make(chan struct{}),
 	}
 }
 
@@ -30,8 +37,10 @@ func (cc *channelConsumer) Start() {
 	// 啟動多個 worker goroutine
 	for i := 0; i < cc.workerCount; i++ {
 		log.Printf("Worker #%d started...", i)
+		cc.wg.Add(1)
 		// 呼叫 cc.Consume 執行 cc.scrapeService.ProcessScrapeTask
 		go func(id int) {
+			defer cc.wg.Done()
 			cc.Consume()
 			log.Printf("Worker #%d stopped.", id) // 真的結束時才印
 		}(i)
@@ -40,12 +49,30 @@ func (cc *channelConsumer) Start() {
 
 // Consume 是 channelConsumer 的執行邏輯
 func (cc *channelConsumer) Consume() {
+	// 每個 callback 都是一個無窮迴圈，持續從 channel 中讀取任務
+	for {
+		select {
+		case <-cc.done:
+			return
+		case articleID, ok := <-cc.queue:
+			if !ok {
+				return
+			}
+			cc.process(articleID)
+		}
+	}
+}
+
+// Stop 停止所有 worker，並等待處理中的任務結束
+func (cc *channelConsumer) Stop() {
+	close(cc.done)
+	cc.wg.Wait()
+}
+
+func (cc *channelConsumer) process(articleID string) {
 	// 確保每個單獨的爬取任務都有自己的超時控制
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), taskTimeout)
 	defer cancel()
 
-	// 每個 callback 都是一個無窮迴圈，持續從 channel 中讀取任務
-	for articleID := range cc.queue {
-		cc.scrapeService.ProcessScrapeTask(ctx, articleID)
-	}
+	cc.scrapeService.ProcessScrapeTask(ctx, articleID)
 }
@@ -0,0 +1,122 @@
+package queue
+
+import (
+	"context"
+	"deeliai/internal/interfaces"
+	"deeliai/internal/service"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// postgresConsumer 是 QueueConsumer 介面基於 PostgreSQL scrape_jobs 資料表的實現
+type postgresConsumer struct {
+	jobRepo           interfaces.ScrapeJobRepository
+	scrapeService     *service.ScrapeService
+	workerCount       int
+	pollInterval      time.Duration
+	visibilityTimeout time.Duration
+	maxDeliveries     int
+	workerPrefix      string
+	done              chan struct{}
+	wg                sync.WaitGroup
+}
+
+func NewPostgresConsumer(repo interfaces.ScrapeJobRepository, s *service.ScrapeService, cnt int, pollInterval, visibilityTimeout time.Duration, maxDeliveries int) interfaces.QueueConsumer {
+	hostname, _ := os.Hostname()
+
+	return &postgresConsumer{
+		jobRepo:           repo,
+		scrapeService:     s,
+		workerCount:       cnt,
+		pollInterval:      pollInterval,
+		visibilityTimeout: visibilityTimeout,
+		maxDeliveries:     maxDeliveries,
+		workerPrefix:      fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		done:              make(chan struct{}),
+	}
+}
+
+// Start 啟動 worker pool
+func (pc *postgresConsumer) Start() {
+	log.Printf("Starting %d postgres scrape workers...", pc.workerCount)
+
+	for i := 0; i < pc.workerCount; i++ {
+		log.Printf("Worker #%d started...", i)
+		pc.wg.Add(1)
+		go func(id int) {
+			defer pc.wg.Done()
+			pc.consume(fmt.Sprintf("%s-%d", pc.workerPrefix, id))
+			log.Printf("Worker #%d stopped.", id)
+		}(i)
+	}
+}
+
+// Consume 以單一 worker 的身份持續處理任務，直到 Stop 被呼叫
+func (pc *postgresConsumer) Consume() {
+	pc.consume(pc.workerPrefix)
+}
+
+// Stop 停止所有 worker，並等待處理中的任務結束
+func (pc *postgresConsumer) Stop() {
+	close(pc.done)
+	pc.wg.Wait()
+}
+
+func (pc *postgresConsumer) consume(workerID string) {
+	for {
+		select {
+		case <-pc.done:
+			return
+		default:
+		}
+
+		// 佇列為空或資料庫暫時無法連線時，等待下一次輪詢
+		if !pc.processNext(workerID) {
+			select {
+			case <-pc.done:
+				return
+			case <-time.After(pc.pollInterval):
+			}
+		}
+	}
+}
+
+// processNext 取出並處理一筆任務，沒有任務可處理時回傳 false
+func (pc *postgresConsumer) processNext(workerID string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), taskTimeout)
+	defer cancel()
+
+	jobs, err := pc.jobRepo.Dequeue(ctx, workerID, 1, pc.visibilityTimeout)
+	if err != nil {
+		log.Printf("Failed to dequeue scrape job: %v", err)
+		return false
+	}
+	if len(jobs) == 0 {
+		return false
+	}
+
+	job := jobs[0]
+	// 超過投遞上限的任務多半會讓 worker 崩潰，直接丟棄避免無限重試
+	if pc.maxDeliveries > 0 && job.Attempts > pc.maxDeliveries {
+		log.Printf("Dropping scrape job %d (%s) after %d deliveries", job.ID, job.Payload, job.Attempts)
+	} else {
+		pc.scrapeService.ProcessScrapeTask(ctx, job.Payload)
+	}
+
+	// 使用獨立的 context，避免任務逾時導致 Ack 失敗而被重複處理
+	ackCtx, ackCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer ackCancel()
+	if err := pc.jobRepo.Ack(ackCtx, &job); err != nil {
+		if errors.Is(err, interfaces.ErrScrapeJobLeaseLost) {
+			log.Printf("Scrape job %d exceeded its visibility timeout and was redelivered to another worker", job.ID)
+		} else {
+			log.Printf("Failed to ack scrape job %d: %v", job.ID, err)
+		}
+	}
+
+	return true
+}
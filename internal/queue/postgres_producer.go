@@ -0,0 +1,30 @@
+package queue
+
+import (
+	"context"
+	"deeliai/internal/interfaces"
+	"time"
+)
+
+// postgresProducer 是 QueueProducer 介面基於 PostgreSQL scrape_jobs 資料表的實現
+// 任務寫入資料庫後即使服務重啟也不會遺失，並可由多台機器共同消化
+type postgresProducer struct {
+	jobRepo interfaces.ScrapeJobRepository
+}
+
+func NewPostgresProducer(repo interfaces.ScrapeJobRepository) interfaces.QueueProducer {
+	return &postgresProducer{jobRepo: repo}
+}
+
+// Produce 將任務寫入 scrape_jobs
+func (p *postgresProducer) Produce(message string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return p.jobRepo.Enqueue(ctx, message)
+}
+
+// Close 資料庫連線由呼叫端管理，這裡不需要釋放資源
+func (p *postgresProducer) Close() error {
+	return nil
+}
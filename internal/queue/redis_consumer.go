@@ -0,0 +1,244 @@
+package queue
+
+import (
+	"context"
+	"deeliai/internal/interfaces"
+	"deeliai/internal/service"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisConsumerOptions 是 Redis Streams consumer 的可調參數
+type RedisConsumerOptions struct {
+	Stream        string
+	Group         string
+	WorkerCount   int
+	Block         time.Duration // XREADGROUP 每次最長阻塞時間
+	ClaimMinIdle  time.Duration // pending entry 閒置超過此時間即視為 worker 已崩潰
+	ClaimInterval time.Duration // 多久執行一次 XAUTOCLAIM
+	MaxDeliveries int64         // 超過投遞次數的任務直接丟棄
+}
+
+// scrapeTaskProcessor 是 consumer 處理任務時需要的行為，由 *service.ScrapeService 實作
+type scrapeTaskProcessor interface {
+	ProcessScrapeTask(ctx context.Context, payload string)
+}
+
+// redisConsumer 是 QueueConsumer 介面基於 Redis Streams consumer group 的實現
+type redisConsumer struct {
+	client        redis.UniversalClient
+	scrapeService scrapeTaskProcessor
+	opts          RedisConsumerOptions
+	consumerName  string
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+// NewRedisConsumer 接受任何 redis.UniversalClient，測試時可直接接上 miniredis 之類的替身
+func NewRedisConsumer(client redis.UniversalClient, s *service.ScrapeService, opts RedisConsumerOptions) interfaces.QueueConsumer {
+	return newRedisConsumer(client, s, opts)
+}
+
+func newRedisConsumer(client redis.UniversalClient, s scrapeTaskProcessor, opts RedisConsumerOptions) *redisConsumer {
+	hostname, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &redisConsumer{
+		client:        client,
+		scrapeService: s,
+		opts:          opts,
+		consumerName:  fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// Start 建立 consumer group，並啟動 worker pool 與 pending entry 回收器
+func (rc *redisConsumer) Start() {
+	if err := rc.ensureGroup(); err != nil {
+		log.Printf("Failed to create redis consumer group %s: %v", rc.opts.Group, err)
+	}
+
+	log.Printf("Starting %d redis scrape workers...", rc.opts.WorkerCount)
+	for i := 0; i < rc.opts.WorkerCount; i++ {
+		log.Printf("Worker #%d started...", i)
+		rc.wg.Add(1)
+		go func(id int) {
+			defer rc.wg.Done()
+			rc.consume(fmt.Sprintf("%s-%d", rc.consumerName, id))
+			log.Printf("Worker #%d stopped.", id)
+		}(i)
+	}
+
+	rc.wg.Add(1)
+	go func() {
+		defer rc.wg.Done()
+		rc.reclaimLoop()
+	}()
+}
+
+// Consume 以單一 consumer 的身份持續處理任務，直到 Stop 被呼叫
+func (rc *redisConsumer) Consume() {
+	rc.consume(rc.consumerName)
+}
+
+// Stop 停止所有 worker，並等待處理中的任務結束
+func (rc *redisConsumer) Stop() {
+	rc.cancel()
+	rc.wg.Wait()
+}
+
+func (rc *redisConsumer) ensureGroup() error {
+	// 從 "0" 開始，讓 group 建立前就已寫入的任務也會被處理
+	err := rc.client.XGroupCreateMkStream(rc.ctx, rc.opts.Stream, rc.opts.Group, "0").Err()
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	return nil
+}
+
+func (rc *redisConsumer) consume(consumer string) {
+	for rc.ctx.Err() == nil {
+		streams, err := rc.client.XReadGroup(rc.ctx, &redis.XReadGroupArgs{
+			Group:    rc.opts.Group,
+			Consumer: consumer,
+			Streams:  []string{rc.opts.Stream, ">"},
+			Count:    1,
+			Block:    rc.opts.Block,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || rc.ctx.Err() != nil {
+				continue
+			}
+
+			log.Printf("Failed to read from redis stream %s: %v", rc.opts.Stream, err)
+			// group 可能因 Redis 重啟而消失，重建後稍待再重試
+			if strings.Contains(err.Error(), "NOGROUP") {
+				_ = rc.ensureGroup()
+			}
+			rc.sleep(time.Second)
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				rc.process(msg)
+			}
+		}
+	}
+}
+
+// reclaimLoop 定期以 XAUTOCLAIM 接手閒置過久的 pending entry (通常代表原 worker 已崩潰)
+func (rc *redisConsumer) reclaimLoop() {
+	ticker := time.NewTicker(rc.opts.ClaimInterval)
+	defer ticker.Stop()
+
+	consumer := rc.consumerName + "-reclaimer"
+	for {
+		select {
+		case <-rc.ctx.Done():
+			return
+		case <-ticker.C:
+			rc.reclaim(consumer)
+		}
+	}
+}
+
+func (rc *redisConsumer) reclaim(consumer string) {
+	start := "0-0"
+	for rc.ctx.Err() == nil {
+		msgs, next, err := rc.client.XAutoClaim(rc.ctx, &redis.XAutoClaimArgs{
+			Stream:   rc.opts.Stream,
+			Group:    rc.opts.Group,
+			MinIdle:  rc.opts.ClaimMinIdle,
+			Start:    start,
+			Count:    10,
+			Consumer: consumer,
+		}).Result()
+		if err != nil {
+			if rc.ctx.Err() == nil {
+				log.Printf("Failed to reclaim pending entries from %s: %v", rc.opts.Stream, err)
+			}
+			return
+		}
+
+		for _, msg := range msgs {
+			if rc.exceededDeliveries(msg.ID) {
+				log.Printf("Dropping redis message %s after too many deliveries", msg.ID)
+				rc.ack(msg.ID)
+				continue
+			}
+
+			log.Printf("Reclaimed redis message %s", msg.ID)
+			rc.process(msg)
+		}
+
+		// 回傳 "0-0" 代表已掃描完整個 pending entries list
+		if next == "0-0" || next == "" {
+			return
+		}
+		start = next
+	}
+}
+
+// exceededDeliveries 查詢訊息被投遞的次數是否已超過上限
+func (rc *redisConsumer) exceededDeliveries(id string) bool {
+	if rc.opts.MaxDeliveries <= 0 {
+		return false
+	}
+
+	pending, err := rc.client.XPendingExt(rc.ctx, &redis.XPendingExtArgs{
+		Stream: rc.opts.Stream,
+		Group:  rc.opts.Group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err != nil || len(pending) == 0 {
+		return false
+	}
+
+	return pending[0].RetryCount > rc.opts.MaxDeliveries
+}
+
+func (rc *redisConsumer) process(msg redis.XMessage) {
+	payload, _ := msg.Values[redisPayloadField].(string)
+	if payload == "" {
+		log.Printf("Dropping redis message %s without payload", msg.ID)
+		rc.ack(msg.ID)
+		return
+	}
+
+	// 確保每個單獨的爬取任務都有自己的超時控制
+	ctx, cancel := context.WithTimeout(context.Background(), taskTimeout)
+	rc.scrapeService.ProcessScrapeTask(ctx, payload)
+	cancel()
+
+	rc.ack(msg.ID)
+}
+
+func (rc *redisConsumer) ack(id string) {
+	// 使用獨立的 context，Stop 後仍能確認已處理完的任務
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := rc.client.XAck(ctx, rc.opts.Stream, rc.opts.Group, id).Err(); err != nil {
+		log.Printf("Failed to ack redis message %s: %v", id, err)
+	}
+}
+
+func (rc *redisConsumer) sleep(d time.Duration) {
+	select {
+	case <-rc.ctx.Done():
+	case <-time.After(d):
+	}
+}
@@ -0,0 +1,169 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	testStream = "scrape"
+	testGroup  = "workers"
+)
+
+// recordingProcessor 記錄收到的任務，取代真正的 ScrapeService
+type recordingProcessor struct {
+	mu       sync.Mutex
+	payloads []string
+	done     chan string
+}
+
+func newRecordingProcessor() *recordingProcessor {
+	return &recordingProcessor{done: make(chan string, 16)}
+}
+
+func (p *recordingProcessor) ProcessScrapeTask(ctx context.Context, payload string) {
+	p.mu.Lock()
+	p.payloads = append(p.payloads, payload)
+	p.mu.Unlock()
+
+	p.done <- payload
+}
+
+func (p *recordingProcessor) processed() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]string(nil), p.payloads...)
+}
+
+func newTestConsumer(t *testing.T, client redis.UniversalClient, processor scrapeTaskProcessor, maxDeliveries int64) *redisConsumer {
+	t.Helper()
+
+	rc := newRedisConsumer(client, processor, RedisConsumerOptions{
+		Stream:        testStream,
+		Group:         testGroup,
+		WorkerCount:   1,
+		Block:         50 * time.Millisecond,
+		ClaimMinIdle:  30 * time.Second,
+		ClaimInterval: time.Hour, // 測試中直接呼叫 reclaim，不等 ticker
+		MaxDeliveries: maxDeliveries,
+	})
+	if err := rc.ensureGroup(); err != nil {
+		t.Fatalf("ensureGroup failed: %v", err)
+	}
+	t.Cleanup(rc.cancel)
+
+	return rc
+}
+
+// deliverWithoutAck 模擬 worker 取出任務後在 Ack 前崩潰，任務留在 pending entries list 中
+func deliverWithoutAck(t *testing.T, client redis.UniversalClient, payload string) string {
+	t.Helper()
+
+	ctx := context.Background()
+	if err := client.XAdd(ctx, &redis.XAddArgs{Stream: testStream, Values: map[string]interface{}{redisPayloadField: payload}}).Err(); err != nil {
+		t.Fatalf("XADD failed: %v", err)
+	}
+
+	streams, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    testGroup,
+		Consumer: "crashed-worker",
+		Streams:  []string{testStream, ">"},
+		Count:    1,
+		Block:    -1,
+	}).Result()
+	if err != nil || len(streams) != 1 || len(streams[0].Messages) != 1 {
+		t.Fatalf("XREADGROUP = %v, %v; want one message", streams, err)
+	}
+
+	return streams[0].Messages[0].ID
+}
+
+func pendingCount(t *testing.T, client redis.UniversalClient) int64 {
+	t.Helper()
+
+	pending, err := client.XPending(context.Background(), testStream, testGroup).Result()
+	if err != nil {
+		t.Fatalf("XPENDING failed: %v", err)
+	}
+
+	return pending.Count
+}
+
+func TestRedisConsumerProcessesAndAcks(t *testing.T) {
+	_, client := newTestRedis(t)
+	processor := newRecordingProcessor()
+	rc := newTestConsumer(t, client, processor, 0)
+
+	rc.Start()
+	defer rc.Stop()
+
+	if err := NewRedisProducer(client, testStream, 0).Produce("page-1"); err != nil {
+		t.Fatalf("Produce failed: %v", err)
+	}
+
+	select {
+	case got := <-processor.done:
+		if got != "page-1" {
+			t.Fatalf("processed %q, want page-1", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not consumed")
+	}
+
+	// Ack 在處理完成後才送出，等待 pending entries list 清空
+	deadline := time.Now().Add(5 * time.Second)
+	for pendingCount(t, client) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("message was not acked")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRedisConsumerReclaimsIdlePendingEntries(t *testing.T) {
+	mr, client := newTestRedis(t)
+	processor := newRecordingProcessor()
+	rc := newTestConsumer(t, client, processor, 3)
+
+	deliverWithoutAck(t, client, "page-1")
+
+	// 還沒閒置超過 ClaimMinIdle，不應被接手
+	rc.reclaim("reclaimer")
+	if got := processor.processed(); len(got) != 0 {
+		t.Fatalf("reclaimed %v before ClaimMinIdle elapsed", got)
+	}
+
+	mr.SetTime(time.Now().Add(time.Minute))
+	rc.reclaim("reclaimer")
+
+	if got := processor.processed(); len(got) != 1 || got[0] != "page-1" {
+		t.Fatalf("processed %v, want [page-1]", got)
+	}
+	if n := pendingCount(t, client); n != 0 {
+		t.Errorf("pending entries = %d after reclaim, want 0", n)
+	}
+}
+
+func TestRedisConsumerDropsEntryAfterMaxDeliveries(t *testing.T) {
+	mr, client := newTestRedis(t)
+	processor := newRecordingProcessor()
+	// 第一次由崩潰的 worker 取出，XAUTOCLAIM 接手時已是第二次投遞
+	rc := newTestConsumer(t, client, processor, 1)
+
+	deliverWithoutAck(t, client, "poison-page")
+
+	mr.SetTime(time.Now().Add(time.Minute))
+	rc.reclaim("reclaimer")
+
+	if got := processor.processed(); len(got) != 0 {
+		t.Fatalf("processed %v, want the entry to be dropped", got)
+	}
+	if n := pendingCount(t, client); n != 0 {
+		t.Errorf("pending entries = %d after drop, want 0", n)
+	}
+}
@@ -0,0 +1,47 @@
+package queue
+
+import (
+	"context"
+	"deeliai/internal/interfaces"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisPayloadField 是任務內容在 Stream entry 中的欄位名稱
+const redisPayloadField = "payload"
+
+// redisProducer 是 QueueProducer 介面基於 Redis Streams 的實現
+type redisProducer struct {
+	client redis.UniversalClient
+	stream string
+	maxLen int64
+}
+
+// NewRedisProducer 接受任何 redis.UniversalClient，測試時可直接接上 miniredis 之類的替身
+func NewRedisProducer(client redis.UniversalClient, stream string, maxLen int64) interfaces.QueueProducer {
+	return &redisProducer{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+// Produce 以 XADD 將任務加入 Stream
+func (p *redisProducer) Produce(message string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// MaxLen 搭配 Approx 讓 Redis 以近似方式修剪 Stream，避免無限成長
+	return p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: p.maxLen > 0,
+		Values: map[string]interface{}{redisPayloadField: message},
+	}).Err()
+}
+
+// Close 關閉 Redis 連線，與 channelProducer 一樣由 Producer 負責釋放共用資源
+func (p *redisProducer) Close() error {
+	return p.client.Close()
+}
@@ -0,0 +1,67 @@
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestRedis 啟動一個 miniredis 並回傳連到它的 client，測試結束時自動關閉
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return mr, client
+}
+
+func TestRedisProducerProduce(t *testing.T) {
+	_, client := newTestRedis(t)
+	producer := NewRedisProducer(client, "scrape", 0)
+
+	for _, payload := range []string{"page-1", "page-2"} {
+		if err := producer.Produce(payload); err != nil {
+			t.Fatalf("Produce(%q) returned error: %v", payload, err)
+		}
+	}
+
+	msgs, err := client.XRange(context.Background(), "scrape", "-", "+").Result()
+	if err != nil {
+		t.Fatalf("XRANGE failed: %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("stream has %d entries, want 2", len(msgs))
+	}
+	for i, want := range []string{"page-1", "page-2"} {
+		if got := msgs[i].Values[redisPayloadField]; got != want {
+			t.Errorf("entry %d payload = %v, want %q", i, got, want)
+		}
+	}
+}
+
+func TestRedisProducerProduceTrimsStream(t *testing.T) {
+	_, client := newTestRedis(t)
+	producer := NewRedisProducer(client, "scrape", 1)
+
+	for _, payload := range []string{"page-1", "page-2", "page-3"} {
+		if err := producer.Produce(payload); err != nil {
+			t.Fatalf("Produce(%q) returned error: %v", payload, err)
+		}
+	}
+
+	// 近似修剪不保證剛好 maxLen 筆，只確認 Stream 沒有無限成長且最新的任務還在
+	msgs, err := client.XRevRangeN(context.Background(), "scrape", "+", "-", 1).Result()
+	if err != nil {
+		t.Fatalf("XREVRANGE failed: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].Values[redisPayloadField] != "page-3" {
+		t.Fatalf("latest entry = %v, want page-3", msgs)
+	}
+	if n := client.XLen(context.Background(), "scrape").Val(); n > 3 {
+		t.Errorf("stream length = %d, want at most 3", n)
+	}
+}
@@ -0,0 +1,95 @@
+package sqlximpl
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"deeliai/internal/interfaces"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// sqlxAdvisoryLock 以 PostgreSQL session-level advisory lock 實作 LeaderLock
+// advisory lock 綁定在資料庫連線上，因此取得鎖後必須持有同一條連線直到釋放
+type sqlxAdvisoryLock struct {
+	db   *sqlx.DB
+	key  int64
+	mu   sync.Mutex
+	conn *sqlx.Conn
+}
+
+func NewAdvisoryLock(db *sqlx.DB, key int64) interfaces.LeaderLock {
+	return &sqlxAdvisoryLock{db: db, key: key}
+}
+
+// TryAcquire 以 pg_try_advisory_lock 嘗試取得鎖
+func (l *sqlxAdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		return true, nil
+	}
+
+	conn, err := l.db.Connx(ctx)
+	if err != nil {
+		slog.Error("Failed to get connection for advisory lock", "error", err)
+		return false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowxContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&acquired); err != nil {
+		conn.Close()
+		slog.Error("Failed to acquire advisory lock", "error", err)
+		return false, err
+	}
+
+	if !acquired {
+		conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Check 確認持有鎖的連線仍然存活；連線中斷時 PostgreSQL 會自動釋放鎖
+func (l *sqlxAdvisoryLock) Check(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return errors.New("advisory lock not held")
+	}
+
+	if err := l.conn.PingContext(ctx); err != nil {
+		l.conn.Close()
+		l.conn = nil
+		slog.Error("Lost advisory lock connection", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// Release 釋放鎖並歸還連線
+func (l *sqlxAdvisoryLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	_, err := l.conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, l.key)
+	l.conn.Close()
+	l.conn = nil
+	if err != nil {
+		slog.Error("Failed to release advisory lock", "error", err)
+		return err
+	}
+
+	return nil
+}
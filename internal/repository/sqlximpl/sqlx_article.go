@@ -2,8 +2,12 @@ package sqlximpl
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"html"
 	"log/slog"
+	"strings"
 	"time"
 
 	"deeliai/internal/interfaces"
@@ -11,8 +15,21 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// articleColumns 是查詢單篇或列表文章時回傳給使用者的欄位，metadata 與爬取狀態來自文章所屬的頁面
+// 使用者自訂的標題與描述優先於爬取到的值
+var articleColumns = `a.id, a.user_email, a.page_id, a.url, a.canonical_url,
+	COALESCE(a.custom_title, p.title) AS title, COALESCE(a.custom_description, p.description) AS description, a.custom_title, a.custom_description, a.notes, ` + tagNames("a.id") + ` AS tags,
+	a.read_at, a.archived_at, a.favorited,
+	p.image_url, p.scrape_status, p.next_attempt_at, p.last_error,
+	p.author, p.site_name, p.favicon_url, p.canonical_link, p.oembed_url, p.language, p.keywords, p.published_at, p.modified_at, p.document_type, p.extras, p.thumbnail_key,
+	a.created_at, GREATEST(a.updated_at, p.updated_at) AS updated_at`
+
+// articleFrom 是查詢文章時的資料來源
+const articleFrom = `articles a JOIN pages p ON p.id = a.page_id`
+
 type sqlxArticleRepository struct {
 	db *sqlx.DB
 }
@@ -21,74 +38,258 @@ func NewArticleRepository(db *sqlx.DB) interfaces.ArticleRepository {
 	return &sqlxArticleRepository{db: db}
 }
 
-// Create 將新文章記錄存入資料庫
+// Create 將使用者與頁面的關聯存入資料庫，使用者已收藏過相同 canonical_url 時回傳 interfaces.ErrArticleExists
 func (r *sqlxArticleRepository) Create(ctx context.Context, article *model.Article) (*model.Article, error) {
 	newArticle := &model.Article{}
-	query := `INSERT INTO articles (user_email, url) VALUES ($1, $2) RETURNING *`
-	// 對於支援 RETURNING 的資料庫 (如 PostgreSQL)，可以這樣取回 ID
-	// 對於 MySQL，需要用 LastInsertId()
-	err := r.db.QueryRowxContext(ctx, query, article.UserEmail, article.URL).StructScan(newArticle)
+	query := `
+		WITH a AS (
+			INSERT INTO articles (user_email, url, canonical_url, page_id) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_email, canonical_url) DO NOTHING
+			RETURNING *
+		)
+		SELECT ` + articleColumns + ` FROM a JOIN pages p ON p.id = a.page_id
+	`
+	err := r.db.QueryRowxContext(ctx, query, article.UserEmail, article.URL, article.CanonicalURL, article.PageID).StructScan(newArticle)
 	if err != nil {
+		// ON CONFLICT DO NOTHING 不會回傳任何資料列
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, interfaces.ErrArticleExists
+		}
 		slog.Error("Failed to create article", "error", err)
 		return nil, err
 	}
 	return newArticle, nil
 }
 
-// UpdateMetadata 更新文章的 Metadata
-func (r *sqlxArticleRepository) UpdateMetadata(ctx context.Context, articleID uuid.UUID, title, description, imageURL string) error {
-	query := `UPDATE articles SET title=$1, description=$2, image_url=$3, scrape_status='success', updated_at=$4 WHERE id=$5`
-	_, err := r.db.ExecContext(ctx, query, title, description, imageURL, time.Now(), articleID)
+// FindByCanonicalURL 以正規化後的網址尋找使用者已收藏的文章，也比對爬取到的 canonical 網址
+// 找不到時回傳 sql.ErrNoRows
+func (r *sqlxArticleRepository) FindByCanonicalURL(ctx context.Context, userEmail, canonicalURL string) (*model.Article, error) {
+	article := &model.Article{}
+	// 提交網址完全相同的優先，其次是最早收藏的
+	query := `
+		SELECT ` + articleColumns + ` FROM ` + articleFrom + `
+		WHERE a.user_email = $1 AND (a.canonical_url = $2 OR p.canonical_url = $2 OR p.scraped_canonical_url = $2)
+		ORDER BY a.canonical_url = $2 DESC, a.created_at
+		LIMIT 1
+	`
+	err := r.db.GetContext(ctx, article, query, userEmail, canonicalURL)
 	if err != nil {
-		slog.Error("Failed to update article metadata", "error", err)
-		return err
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("Failed to find article by canonical url", "error", err)
+		}
+		return nil, err
 	}
 
-	return nil
+	return article, nil
 }
 
-// MarkScrapeFailed 標記爬取失敗並增加重試次數
-func (r *sqlxArticleRepository) MarkScrapeFailed(ctx context.Context, articleID uuid.UUID) error {
-	query := `UPDATE articles SET scrape_status='failed', retry_count=retry_count+1, updated_at=$1 WHERE id=$2`
-	_, err := r.db.ExecContext(ctx, query, time.Now(), articleID)
-	if err != nil {
-		slog.Error("Failed to marke scrape failed", "error", err)
-		return err
+// articleListFrom 是文章列表的資料來源，多合併使用者自己的評分以便依評分篩選、排序
+const articleListFrom = articleFrom + ` LEFT JOIN ratings r ON r.article_id = a.id AND r.user_email = a.user_email`
+
+// articleListSource 回傳文章列表的資料來源，依收藏夾篩選時再合併 collection_articles 以取得文章在收藏夾中的順序
+func articleListSource(f model.ArticleFilter) string {
+	if f.CollectionID == nil {
+		return articleListFrom
 	}
+	return articleListFrom + ` JOIN collection_articles ca ON ca.article_id = a.id`
+}
 
-	return nil
+// articleSortKeys 是各排序欄位的 SQL 運算式，以及將 cursor 中的文字轉回原型別的 cast
+var articleSortKeys = map[string]struct{ expr, cast string }{
+	model.ArticleSortCreatedAt: {"a.created_at", "timestamptz"},
+	model.ArticleSortRating:    {"COALESCE(r.scores, 0)", "int"},
+	model.ArticleSortTitle:     {"lower(COALESCE(a.custom_title, p.title, ''))", "text"},
+	model.ArticleSortPosition:  {"ca.position", "int"},
+}
+
+type articleRow struct {
+	model.Article
+	SortKey string `db:"sort_key"`
 }
 
-// ListByUserEmail 根據使用者 ID 取得文章列表
-func (r *sqlxArticleRepository) ListByUserEmail(ctx context.Context, userEmail string, limit, offset int) ([]model.Article, error) {
-	var articles []model.Article
-	query := `SELECT id, user_email, url, title, description, image_url, scrape_status, created_at FROM articles WHERE user_email = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
-	err := r.db.SelectContext(ctx, &articles, query, userEmail, limit, offset)
+// ListByUserEmail 以 keyset 分頁取得使用者的文章列表，依 (排序值, id) 排序，新收藏的文章不會讓後面的頁面重複或漏掉
+// 還有下一頁時回傳最後一篇文章的位置
+func (r *sqlxArticleRepository) ListByUserEmail(ctx context.Context, userEmail string, q model.ArticleQuery) ([]model.Article, *model.ArticleCursor, error) {
+	sortKey, ok := articleSortKeys[q.Sort]
+	if !ok || (q.Sort == model.ArticleSortPosition && q.Filter.CollectionID == nil) {
+		return nil, nil, fmt.Errorf("unknown sort %q", q.Sort)
+	}
+
+	where, args := articleFilterClause(userEmail, q.Filter)
+	direction, comparison := "ASC", ">"
+	if q.Desc {
+		direction, comparison = "DESC", "<"
+	}
+	if q.After != nil {
+		args = append(args, q.After.SortKey, q.After.ID)
+		where += fmt.Sprintf(" AND (%s, a.id) %s ($%d::%s, $%d)", sortKey.expr, comparison, len(args)-1, sortKey.cast, len(args))
+	}
+	// 多取一筆用來判斷是否還有下一頁
+	args = append(args, q.Limit+1)
+
+	query := fmt.Sprintf(`
+		SELECT `+articleColumns+`, (%[1]s)::text AS sort_key
+		FROM `+articleListSource(q.Filter)+`
+		WHERE %[2]s
+		ORDER BY %[1]s %[3]s, a.id %[3]s
+		LIMIT $%[4]d
+	`, sortKey.expr, where, direction, len(args))
+
+	var rows []articleRow
+	err := r.db.SelectContext(ctx, &rows, query, args...)
 	if err != nil {
 		slog.Error("Failed to list articles by email", "error", err)
-		return nil, err
+		return nil, nil, err
+	}
+
+	var next *model.ArticleCursor
+	if len(rows) > q.Limit {
+		rows = rows[:q.Limit]
+		last := rows[len(rows)-1]
+		next = &model.ArticleCursor{SortKey: last.SortKey, ID: last.ID}
+	}
+
+	articles := make([]model.Article, len(rows))
+	for i, row := range rows {
+		articles[i] = row.Article
 	}
 
-	return articles, nil
+	return articles, next, nil
 }
 
-// FindByID 根據文章 ID 取得單篇文章
-func (r *sqlxArticleRepository) FindByID(ctx context.Context, articleID uuid.UUID) (*model.Article, error) {
-	article := &model.Article{}
-	query := `SELECT id, user_email, url, title, description, image_url, scrape_status, created_at FROM articles WHERE id = $1 LIMIT 1`
-	err := r.db.GetContext(ctx, article, query, articleID)
+// CountByUserEmail 計算符合篩選條件的文章數
+func (r *sqlxArticleRepository) CountByUserEmail(ctx context.Context, userEmail string, filter model.ArticleFilter) (int, error) {
+	where, args := articleFilterClause(userEmail, filter)
+
+	var total int
+	err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM `+articleListSource(filter)+` WHERE `+where, args...)
+	if err != nil {
+		slog.Error("Failed to count articles by email", "error", err)
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// articleFilterClause 將篩選條件轉為 WHERE 子句與對應的參數
+func articleFilterClause(userEmail string, f model.ArticleFilter) (string, []any) {
+	args := []any{userEmail}
+	conditions := []string{"a.user_email = $1"}
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	if f.Status != "" {
+		add("p.scrape_status = ?", f.Status)
+	}
+	if f.Domain != "" {
+		// 網域同時涵蓋子網域 (例如 example.com 也會比對到 blog.example.com)
+		add("("+urlHost("a.url")+" = ? OR "+urlHost("a.url")+" LIKE '%.' || ?)", strings.ToLower(f.Domain))
+	}
+	if f.Tag != "" {
+		add("EXISTS (SELECT 1 FROM article_tags atg JOIN tags t ON t.id = atg.tag_id WHERE atg.article_id = a.id AND t.name = ?)", f.Tag)
+	}
+	if f.MinRating > 0 {
+		add("r.scores >= ?", f.MinRating)
+	}
+	if f.MaxRating > 0 {
+		add("r.scores <= ?", f.MaxRating)
+	}
+	if f.CreatedAfter != nil {
+		add("a.created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		add("a.created_at < ?", *f.CreatedBefore)
+	}
+	if f.CollectionID != nil {
+		add("ca.collection_id = ?", *f.CollectionID)
+	}
+	if f.Read != nil {
+		add("(a.read_at IS NOT NULL) = ?", *f.Read)
+	}
+	if f.Archived != nil {
+		add("(a.archived_at IS NOT NULL) = ?", *f.Archived)
+	}
+	if f.Favorited != nil {
+		add("a.favorited = ?", *f.Favorited)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// urlHost 回傳取出網址欄位中 host 部分 (小寫) 的 SQL 運算式
+func urlHost(column string) string {
+	return "lower(substring(" + column + " from '^[A-Za-z][A-Za-z0-9+.-]*://(?:[^@/]*@)?([^/:?#]+)'))"
+}
+
+// 搜尋摘要中標示符合字詞的記號，使用 Unicode 私用區字元，HTML escape 之後再換成 <mark> 標籤
+const (
+	headlineStart = "\ue000"
+	headlineStop  = "\ue001"
+)
+
+// Search 以全文搜尋使用者的文章，依相關度排序
+// tsvector 涵蓋標題、描述、網址、筆記與標籤；中文等沒有空白分詞的內容另以 pg_trgm 的 ILIKE 比對標題與描述
+func (r *sqlxArticleRepository) Search(ctx context.Context, userEmail, q string, limit, offset int) ([]model.ArticleSearchResult, error) {
+	results := []model.ArticleSearchResult{}
+	// 先分別在 articles、pages 與標籤上找出符合的文章再合併，每個分支的條件只涉及單一資料表，
+	// 才能使用各自的 GIN 索引 (search_vector 與 *_trgm)；跨資料表的 OR 只能逐筆掃描
+	query := `
+		WITH q AS (SELECT websearch_to_tsquery('simple', $2) AS query),
+		matched AS (
+			SELECT a.id FROM articles a, q
+			WHERE a.user_email = $1
+			  AND (a.search_vector @@ q.query OR a.custom_title ILIKE $3 OR a.custom_description ILIKE $3)
+			UNION
+			SELECT a.id FROM pages p JOIN articles a ON a.page_id = p.id, q
+			WHERE a.user_email = $1
+			  AND (p.search_vector @@ q.query OR p.title ILIKE $3 OR p.description ILIKE $3)
+			UNION
+			SELECT atg.article_id FROM tags t JOIN article_tags atg ON atg.tag_id = t.id
+			WHERE t.user_email = $1
+			GROUP BY atg.article_id
+			HAVING to_tsvector('simple', string_agg(t.name, ' ')) @@ (SELECT query FROM q)
+		)
+		SELECT ` + articleColumns + `,
+			ts_rank(a.search_vector || p.search_vector || COALESCE(tg.search_vector, ''::tsvector), q.query)
+				+ similarity(COALESCE(a.custom_title, p.title, ''), $2) AS rank,
+			ts_headline('simple',
+				concat_ws(' ', COALESCE(a.custom_title, p.title), COALESCE(a.custom_description, p.description), a.notes),
+				q.query, 'StartSel=` + headlineStart + `, StopSel=` + headlineStop + `, MaxWords=35, MinWords=15, MaxFragments=2') AS snippet
+		FROM ` + articleFrom + `
+		JOIN matched m ON m.id = a.id
+		LEFT JOIN LATERAL (
+			SELECT setweight(to_tsvector('simple', string_agg(t.name, ' ')), 'A') AS search_vector
+			FROM article_tags atg JOIN tags t ON t.id = atg.tag_id
+			WHERE atg.article_id = a.id
+		) tg ON TRUE, q
+		ORDER BY rank DESC, a.created_at DESC, a.id
+		LIMIT $4 OFFSET $5
+	`
+	pattern := "%" + likeEscaper.Replace(q) + "%"
+	err := r.db.SelectContext(ctx, &results, query, userEmail, q, pattern, limit, offset)
 	if err != nil {
-		slog.Error("Failed to get article by id", "error", err)
+		slog.Error("Failed to search articles", "error", err)
 		return nil, err
 	}
 
-	return article, nil
+	for i := range results {
+		snippet := html.EscapeString(results[i].Snippet)
+		snippet = strings.ReplaceAll(snippet, headlineStart, "<mark>")
+		results[i].Snippet = strings.ReplaceAll(snippet, headlineStop, "</mark>")
+	}
+
+	return results, nil
 }
 
+// likeEscaper 跳脫 LIKE 的萬用字元，讓使用者輸入的 % 與 _ 以字面比對
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 // FindByIDAndUserEmail 根據文章 ID 和使用者 ID 取得單篇文章
 func (r *sqlxArticleRepository) FindByIDAndUserEmail(ctx context.Context, articleID uuid.UUID, userEmail string) (*model.Article, error) {
 	article := &model.Article{}
-	query := `SELECT id, user_email, url, title, description, image_url, scrape_status, created_at FROM articles WHERE id = $1 AND user_email = $2 LIMIT 1`
+	query := `SELECT ` + articleColumns + ` FROM ` + articleFrom + ` WHERE a.id = $1 AND a.user_email = $2 LIMIT 1`
 	err := r.db.GetContext(ctx, article, query, articleID, userEmail)
 	if err != nil {
 		slog.Error("Failed to get article by id & email", "error", err)
@@ -98,88 +299,106 @@ func (r *sqlxArticleRepository) FindByIDAndUserEmail(ctx context.Context, articl
 	return article, nil
 }
 
-// Delete 刪除文章
-func (r *sqlxArticleRepository) Delete(ctx context.Context, articleID uuid.UUID, userEmail string) error {
-	query := `DELETE FROM articles WHERE id = $1 AND user_email = $2`
-	res, err := r.db.ExecContext(ctx, query, articleID, userEmail)
-	if err != nil {
-		slog.Error("Failed to delete article", "error", err)
-		return err
-	}
-
-	if rowsAffected, err := res.RowsAffected(); rowsAffected == 0 {
-		slog.Error("article not found or user not authorized", "error", err)
-		return errors.New("article not found or user not authorized")
+// Update 修改使用者自訂的欄位，文章不存在或不屬於使用者時回傳 sql.ErrNoRows
+// 自訂值存在 articles 上，頁面重新爬取時不會被覆蓋
+func (r *sqlxArticleRepository) Update(ctx context.Context, articleID uuid.UUID, userEmail string, update model.ArticleUpdate) (*model.Article, error) {
+	sets := []string{"updated_at = $1"}
+	args := []any{time.Now()}
+	for _, field := range []struct {
+		column string
+		value  *string
+	}{
+		{"custom_title", update.CustomTitle},
+		{"custom_description", update.CustomDescription},
+		{"notes", update.Notes},
+	} {
+		if field.value == nil {
+			continue
+		}
+		args = append(args, nullString(*field.value))
+		sets = append(sets, fmt.Sprintf("%s = $%d", field.column, len(args)))
 	}
 
-	return nil
-}
-
-// FindFailedScrapes 尋找失敗且重試次數未達上限的文章
-func (r *sqlxArticleRepository) FindFailedScrapes(ctx context.Context) ([]model.Article, error) {
-	var articles []model.Article
-	query := `SELECT id, url, retry_count FROM articles WHERE scrape_status = 'failed' AND retry_count < 3`
-	err := r.db.SelectContext(ctx, &articles, query)
+	article := &model.Article{}
+	args = append(args, articleID, userEmail)
+	query := fmt.Sprintf(`
+		WITH a AS (
+			UPDATE articles SET %s
+			WHERE id = $%d AND user_email = $%d
+			RETURNING *
+		)
+		SELECT `+articleColumns+` FROM a JOIN pages p ON p.id = a.page_id
+	`, strings.Join(sets, ", "), len(args)-1, len(args))
+	err := r.db.QueryRowxContext(ctx, query, args...).StructScan(article)
 	if err != nil {
-		slog.Error("Failed to get failed scrapes", "error", err)
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("Failed to update article", "error", err)
+		}
 		return nil, err
 	}
 
-	return articles, nil
+	return article, nil
 }
 
-type ArticleScore struct {
-	model.Article
-	Score int `db:"score"`
-}
+// UpdateState 修改使用者文章的閱讀狀態，回傳實際修改的文章數；不屬於使用者的文章會被略過
+// 重複標記為已讀或封存時保留第一次的時間；閱讀狀態不影響 updated_at
+func (r *sqlxArticleRepository) UpdateState(ctx context.Context, userEmail string, articleIDs []uuid.UUID, update model.ArticleStateUpdate) (int, error) {
+	var sets []string
+	args := []any{userEmail, pq.Array(articleIDs)}
+	for _, field := range []struct {
+		column string
+		value  *bool
+	}{
+		{"read_at", update.Read},
+		{"archived_at", update.Archived},
+	} {
+		if field.value == nil {
+			continue
+		}
+		args = append(args, *field.value)
+		sets = append(sets, fmt.Sprintf("%[1]s = CASE WHEN $%[2]d::boolean THEN COALESCE(%[1]s, now()) END", field.column, len(args)))
+	}
+	if update.Favorited != nil {
+		args = append(args, *update.Favorited)
+		sets = append(sets, fmt.Sprintf("favorited = $%d", len(args)))
+	}
+	if len(sets) == 0 {
+		return 0, nil
+	}
 
-func (r *sqlxArticleRepository) ListRecommendArticles(ctx context.Context, userEmail string) ([]model.Article, error) {
-	query := `
-        WITH user_tag_weights AS (
-            SELECT unnest(tags) AS tag, SUM(scores) AS weight
-            FROM ratings
-            WHERE user_email = $1
-            GROUP BY tag
-        )
-        SELECT a.*, COALESCE(SUM(t.weight), 0) AS score
-        FROM articles a
-        JOIN ratings r ON a.id = r.article_id
-        JOIN LATERAL unnest(r.tags) AS rt(tag) ON TRUE
-        LEFT JOIN user_tag_weights t ON rt.tag = t.tag
-        WHERE NOT EXISTS (
-            SELECT 1 FROM ratings r2
-            WHERE r2.article_id = a.id
-              AND r2.user_email = $1
-        )
-        GROUP BY a.id
-        ORDER BY score DESC
-        LIMIT 10
-    `
-
-	var articles []ArticleScore
-	err := r.db.SelectContext(ctx, &articles, query, userEmail)
+	query := `UPDATE articles SET ` + strings.Join(sets, ", ") + ` WHERE user_email = $1 AND id = ANY($2::uuid[])`
+	res, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
-		slog.Error("Failed to find recommend articles", "error", err)
-		return nil, err
+		slog.Error("Failed to update article state", "error", err)
+		return 0, err
 	}
 
-	result := make([]model.Article, len(articles))
-	for i, a := range articles {
-		result[i] = a.Article
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
 	}
 
-	return result, nil
+	return int(updated), nil
 }
 
-// FindLatestArticles 找出最新的文章
-func (r *sqlxArticleRepository) FindLatestArticles(ctx context.Context, userEmail string, limit int) ([]model.Article, error) {
-	var articles []model.Article
-	query := `SELECT id, url, title, description, image_url FROM articles WHERE user_email != $1 ORDER BY created_at DESC LIMIT $2`
-	err := r.db.SelectContext(ctx, &articles, query, userEmail, limit)
+// Delete 刪除文章
+func (r *sqlxArticleRepository) Delete(ctx context.Context, articleID uuid.UUID, userEmail string) error {
+	query := `DELETE FROM articles WHERE id = $1 AND user_email = $2`
+	res, err := r.db.ExecContext(ctx, query, articleID, userEmail)
 	if err != nil {
-		slog.Error("Failed to find latest articles", "error", err)
-		return nil, err
+		slog.Error("Failed to delete article", "error", err)
+		return err
+	}
+
+	if rowsAffected, err := res.RowsAffected(); rowsAffected == 0 {
+		slog.Error("article not found or user not authorized", "error", err)
+		return errors.New("article not found or user not authorized")
 	}
 
-	return articles, nil
+	return nil
+}
+
+// nullString 將空字串轉為 NULL，讓「沒有爬到」與「爬到空值」在資料庫中一致
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
 }
@@ -0,0 +1,224 @@
+package sqlximpl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"deeliai/internal/interfaces"
+	"deeliai/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// collectionColumns 是查詢收藏夾時回傳的欄位，包含其中的文章數
+const collectionColumns = `c.id, c.user_email, c.name, c.description, c.is_default,
+	(SELECT COUNT(*) FROM collection_articles ca WHERE ca.collection_id = c.id) AS article_count,
+	c.created_at, c.updated_at`
+
+type sqlxCollectionRepository struct {
+	db *sqlx.DB
+}
+
+func NewCollectionRepository(db *sqlx.DB) interfaces.CollectionRepository {
+	return &sqlxCollectionRepository{db: db}
+}
+
+// Create 建立收藏夾，使用者已有同名的收藏夾時回傳 interfaces.ErrCollectionExists
+func (r *sqlxCollectionRepository) Create(ctx context.Context, collection *model.Collection) (*model.Collection, error) {
+	newCollection := &model.Collection{}
+	query := `
+		WITH c AS (
+			INSERT INTO collections (user_email, name, description) VALUES ($1, $2, $3)
+			ON CONFLICT (user_email, name) DO NOTHING
+			RETURNING *
+		)
+		SELECT ` + collectionColumns + ` FROM c
+	`
+	err := r.db.GetContext(ctx, newCollection, query, collection.UserEmail, collection.Name, collection.Description)
+	if err != nil {
+		// ON CONFLICT DO NOTHING 不會回傳任何資料列
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, interfaces.ErrCollectionExists
+		}
+		slog.Error("Failed to create collection", "error", err)
+		return nil, err
+	}
+
+	return newCollection, nil
+}
+
+// FindOrCreateDefault 取得使用者的 Inbox，還沒有時建立
+func (r *sqlxCollectionRepository) FindOrCreateDefault(ctx context.Context, userEmail string) (*model.Collection, error) {
+	collection := &model.Collection{}
+	// 同時建立時只有一個會成功，另一個由後半段的查詢取得；新建立的資料列在同一個 statement 中看不到，所以用 UNION
+	query := `
+		WITH c AS (
+			INSERT INTO collections (user_email, name, is_default) VALUES ($1, $2, true)
+			ON CONFLICT DO NOTHING
+			RETURNING *
+		)
+		SELECT ` + collectionColumns + ` FROM c
+		UNION ALL
+		SELECT ` + collectionColumns + ` FROM collections c WHERE c.user_email = $1 AND c.is_default
+		LIMIT 1
+	`
+	err := r.db.GetContext(ctx, collection, query, userEmail, model.DefaultCollectionName)
+	if err != nil {
+		slog.Error("Failed to find or create default collection", "error", err)
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// ListByUserEmail 取得使用者所有收藏夾，Inbox 排在最前面，其餘依名稱排序
+func (r *sqlxCollectionRepository) ListByUserEmail(ctx context.Context, userEmail string) ([]model.Collection, error) {
+	collections := []model.Collection{}
+	query := `SELECT ` + collectionColumns + ` FROM collections c WHERE c.user_email = $1 ORDER BY c.is_default DESC, lower(c.name), c.id`
+	err := r.db.SelectContext(ctx, &collections, query, userEmail)
+	if err != nil {
+		slog.Error("Failed to list collections by email", "error", err)
+		return nil, err
+	}
+
+	return collections, nil
+}
+
+// FindByIDAndUserEmail 取得使用者的單一收藏夾，找不到時回傳 sql.ErrNoRows
+func (r *sqlxCollectionRepository) FindByIDAndUserEmail(ctx context.Context, collectionID uuid.UUID, userEmail string) (*model.Collection, error) {
+	collection := &model.Collection{}
+	query := `SELECT ` + collectionColumns + ` FROM collections c WHERE c.id = $1 AND c.user_email = $2`
+	err := r.db.GetContext(ctx, collection, query, collectionID, userEmail)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("Failed to get collection by id & email", "error", err)
+		}
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// Update 修改收藏夾的名稱或描述，找不到時回傳 sql.ErrNoRows，與其他收藏夾同名時回傳 interfaces.ErrCollectionExists
+func (r *sqlxCollectionRepository) Update(ctx context.Context, collectionID uuid.UUID, userEmail string, update model.CollectionUpdate) (*model.Collection, error) {
+	sets := []string{"updated_at = $1"}
+	args := []any{time.Now()}
+	if update.Name != nil {
+		args = append(args, *update.Name)
+		sets = append(sets, fmt.Sprintf("name = $%d", len(args)))
+	}
+	if update.Description != nil {
+		args = append(args, nullString(*update.Description))
+		sets = append(sets, fmt.Sprintf("description = $%d", len(args)))
+	}
+
+	collection := &model.Collection{}
+	args = append(args, collectionID, userEmail)
+	query := fmt.Sprintf(`
+		WITH c AS (
+			UPDATE collections SET %s
+			WHERE id = $%d AND user_email = $%d
+			RETURNING *
+		)
+		SELECT `+collectionColumns+` FROM c
+	`, strings.Join(sets, ", "), len(args)-1, len(args))
+	err := r.db.GetContext(ctx, collection, query, args...)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, interfaces.ErrCollectionExists
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("Failed to update collection", "error", err)
+		}
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// Delete 刪除收藏夾，其中的文章仍保留在使用者的收藏中；Inbox 不會被刪除，找不到時回傳 sql.ErrNoRows
+func (r *sqlxCollectionRepository) Delete(ctx context.Context, collectionID uuid.UUID, userEmail string) error {
+	query := `DELETE FROM collections WHERE id = $1 AND user_email = $2 AND NOT is_default`
+	res, err := r.db.ExecContext(ctx, query, collectionID, userEmail)
+	if err != nil {
+		slog.Error("Failed to delete collection", "error", err)
+		return err
+	}
+
+	if rowsAffected, err := res.RowsAffected(); err != nil || rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// AddArticle 將文章加到收藏夾的最後面，文章已在收藏夾中時不做任何事
+func (r *sqlxCollectionRepository) AddArticle(ctx context.Context, collectionID, articleID uuid.UUID) error {
+	query := `
+		INSERT INTO collection_articles (collection_id, article_id, position)
+		SELECT $1, $2, COALESCE(MAX(position), 0) + 1 FROM collection_articles WHERE collection_id = $1
+		ON CONFLICT DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, collectionID, articleID)
+	if err != nil {
+		slog.Error("Failed to add article to collection", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveArticle 將文章移出收藏夾，文章不在收藏夾中時回傳 sql.ErrNoRows
+func (r *sqlxCollectionRepository) RemoveArticle(ctx context.Context, collectionID, articleID uuid.UUID) error {
+	query := `DELETE FROM collection_articles WHERE collection_id = $1 AND article_id = $2`
+	res, err := r.db.ExecContext(ctx, query, collectionID, articleID)
+	if err != nil {
+		slog.Error("Failed to remove article from collection", "error", err)
+		return err
+	}
+
+	if rowsAffected, err := res.RowsAffected(); err != nil || rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// MoveArticle 將文章移到收藏夾中的第 position 個 (從 1 開始，超過文章數時移到最後)，其餘文章依原本的順序重新編號
+// 文章不在收藏夾中時回傳 sql.ErrNoRows
+func (r *sqlxCollectionRepository) MoveArticle(ctx context.Context, collectionID, articleID uuid.UUID, position int) error {
+	query := `
+		WITH ordered AS (
+			SELECT article_id, row_number() OVER (ORDER BY position, article_id) AS rn
+			FROM collection_articles
+			WHERE collection_id = $1 AND article_id <> $2
+		), renumbered AS (
+			SELECT article_id, CASE WHEN rn >= $3::int THEN rn + 1 ELSE rn END AS position FROM ordered
+			UNION ALL
+			SELECT $2::uuid, LEAST($3::int, (SELECT COUNT(*) FROM ordered) + 1)
+		)
+		UPDATE collection_articles ca SET position = n.position
+		FROM renumbered n
+		WHERE ca.collection_id = $1 AND ca.article_id = n.article_id
+		  AND EXISTS (SELECT 1 FROM collection_articles WHERE collection_id = $1 AND article_id = $2)
+	`
+	res, err := r.db.ExecContext(ctx, query, collectionID, articleID, position)
+	if err != nil {
+		slog.Error("Failed to move article in collection", "error", err)
+		return err
+	}
+
+	if rowsAffected, err := res.RowsAffected(); err != nil || rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
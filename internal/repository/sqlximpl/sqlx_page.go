@@ -0,0 +1,378 @@
+package sqlximpl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"deeliai/internal/interfaces"
+	"deeliai/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// pageColumns 是查詢頁面時回傳的欄位
+const pageColumns = `id, canonical_url, url, title, description, image_url, author, site_name, favicon_url, canonical_link, scraped_canonical_url,
+	oembed_url, language, keywords, published_at, modified_at, document_type, extras, thumbnail_key,
+	scrape_status, retry_count, next_attempt_at, last_error, scraped_at, created_at, updated_at`
+
+type sqlxPageRepository struct {
+	db *sqlx.DB
+}
+
+func NewPageRepository(db *sqlx.DB) interfaces.PageRepository {
+	return &sqlxPageRepository{db: db}
+}
+
+// FindOrCreate 取得正規化網址對應的頁面，沒有時以 url 建立一個待爬取的頁面
+// 網址與已爬取頁面宣告的 canonical 網址相同時也視為同一頁面
+func (r *sqlxPageRepository) FindOrCreate(ctx context.Context, canonicalURL, url string) (*model.Page, bool, error) {
+	page, err := r.findByCanonicalURL(ctx, canonicalURL)
+	if err == nil {
+		return page, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, err
+	}
+
+	page = &model.Page{}
+	query := `
+		INSERT INTO pages (canonical_url, url) VALUES ($1, $2)
+		ON CONFLICT (canonical_url) DO NOTHING
+		RETURNING ` + pageColumns
+	err = r.db.QueryRowxContext(ctx, query, canonicalURL, url).StructScan(page)
+	if errors.Is(err, sql.ErrNoRows) {
+		// 同時有其他請求建立了同一個頁面
+		page, err = r.findByCanonicalURL(ctx, canonicalURL)
+		if err != nil {
+			return nil, false, err
+		}
+		return page, false, nil
+	}
+	if err != nil {
+		slog.Error("Failed to create page", "error", err)
+		return nil, false, err
+	}
+
+	return page, true, nil
+}
+
+// findByCanonicalURL 以正規化網址尋找頁面，canonical_url 完全相同的優先
+func (r *sqlxPageRepository) findByCanonicalURL(ctx context.Context, canonicalURL string) (*model.Page, error) {
+	page := &model.Page{}
+	query := `
+		SELECT ` + pageColumns + ` FROM pages
+		WHERE canonical_url = $1 OR scraped_canonical_url = $1
+		ORDER BY canonical_url = $1 DESC, created_at
+		LIMIT 1
+	`
+	err := r.db.GetContext(ctx, page, query, canonicalURL)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("Failed to find page by canonical url", "error", err)
+		}
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// FindByID 根據頁面 ID 取得頁面
+func (r *sqlxPageRepository) FindByID(ctx context.Context, pageID uuid.UUID) (*model.Page, error) {
+	page := &model.Page{}
+	query := `SELECT ` + pageColumns + ` FROM pages WHERE id = $1`
+	err := r.db.GetContext(ctx, page, query, pageID)
+	if err != nil {
+		slog.Error("Failed to get page by id", "error", err)
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// UpdateMetadata 更新頁面的 Metadata 並記錄爬取成功的時間，沒有找到的欄位存為 NULL
+func (r *sqlxPageRepository) UpdateMetadata(ctx context.Context, pageID uuid.UUID, meta *model.ArticleMetadata) error {
+	query := `
+		UPDATE pages
+		SET title=$1, description=$2, image_url=$3,
+			author=$4, site_name=$5, favicon_url=$6, canonical_link=$7, oembed_url=$8, language=$9, keywords=$10, published_at=$11, modified_at=$12, document_type=$13, extras=$14,
+			scraped_canonical_url=$15, scrape_status='success', retry_count=0, next_attempt_at=NULL, last_error=NULL, scraped_at=$16, updated_at=$16
+		WHERE id=$17
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		meta.Title, meta.Description, meta.ImageURL,
+		nullString(meta.Author), nullString(meta.SiteName), nullString(meta.FaviconURL), nullString(meta.CanonicalLink), nullString(meta.OEmbedURL),
+		nullString(meta.Language), pq.Array(meta.Keywords), meta.PublishedAt, meta.ModifiedAt, nullString(meta.DocumentType), meta.Extras,
+		nullString(meta.CanonicalURL), time.Now(), pageID)
+	if err != nil {
+		slog.Error("Failed to update page metadata", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// SetThumbnailKey 記錄預覽圖縮圖在 BlobStore 中的 key
+func (r *sqlxPageRepository) SetThumbnailKey(ctx context.Context, pageID uuid.UUID, key string) error {
+	query := `UPDATE pages SET thumbnail_key=$1, updated_at=$2 WHERE id=$3`
+	_, err := r.db.ExecContext(ctx, query, nullString(key), time.Now(), pageID)
+	if err != nil {
+		slog.Error("Failed to set page thumbnail key", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkScrapeFailed 標記爬取失敗、增加重試次數並設定下一次重試時間
+func (r *sqlxPageRepository) MarkScrapeFailed(ctx context.Context, pageID uuid.UUID, nextAttemptAt time.Time, lastError string) error {
+	query := `UPDATE pages SET scrape_status='failed', retry_count=retry_count+1, next_attempt_at=$1, last_error=$2, updated_at=$3 WHERE id=$4`
+	_, err := r.db.ExecContext(ctx, query, nextAttemptAt, lastError, time.Now(), pageID)
+	if err != nil {
+		slog.Error("Failed to marke scrape failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkScrapeFailedPermanently 標記爬取永久失敗，排程器不會再重試
+func (r *sqlxPageRepository) MarkScrapeFailedPermanently(ctx context.Context, pageID uuid.UUID, lastError string) error {
+	query := `UPDATE pages SET scrape_status='failed_permanent', retry_count=retry_count+1, next_attempt_at=NULL, last_error=$1, updated_at=$2 WHERE id=$3`
+	_, err := r.db.ExecContext(ctx, query, lastError, time.Now(), pageID)
+	if err != nil {
+		slog.Error("Failed to mark scrape permanently failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkScrapeDeferred 目標主機忙碌時延後爬取，不增加重試次數
+func (r *sqlxPageRepository) MarkScrapeDeferred(ctx context.Context, pageID uuid.UUID, nextAttemptAt time.Time) error {
+	query := `UPDATE pages SET scrape_status='deferred', next_attempt_at=$1, updated_at=$2 WHERE id=$3`
+	_, err := r.db.ExecContext(ctx, query, nextAttemptAt, time.Now(), pageID)
+	if err != nil {
+		slog.Error("Failed to mark scrape deferred", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ClaimDueScrapes 將失敗或延後、且已到達 next_attempt_at 的頁面改回待爬取並回傳其 ID，最多 limit 筆 (重試次數上限由 RetryPolicy 決定)
+// 以單一 UPDATE 認領，下一次檢查或其他排程器不會再拿到同一批頁面，避免重複排入佇列；retry_count 保留不重設
+// 限制筆數避免中斷恢復後一次把所有頁面改為待爬取，其餘留給下一次檢查
+func (r *sqlxPageRepository) ClaimDueScrapes(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	query := `
+		UPDATE pages SET scrape_status='pending', next_attempt_at=NULL, updated_at=$1
+		WHERE id IN (
+			SELECT id FROM pages
+			WHERE scrape_status IN ('failed', 'deferred') AND (next_attempt_at IS NULL OR next_attempt_at <= $1)
+			ORDER BY next_attempt_at NULLS FIRST
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id
+	`
+	err := r.db.SelectContext(ctx, &ids, query, time.Now(), limit)
+	if err != nil {
+		slog.Error("Failed to claim due scrapes", "error", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// resetScrapeSet 是將頁面重設為待爬取狀態並清除重試紀錄的 SET 子句
+const resetScrapeSet = `scrape_status='pending', retry_count=0, next_attempt_at=NULL, last_error=NULL`
+
+// ResetScrape 將頁面重設為待爬取狀態並清除重試紀錄
+func (r *sqlxPageRepository) ResetScrape(ctx context.Context, pageID uuid.UUID) error {
+	query := `UPDATE pages SET ` + resetScrapeSet + `, updated_at=$1 WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, query, time.Now(), pageID)
+	if err != nil {
+		slog.Error("Failed to reset page scrape", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ResetIfStale 頁面上次爬取成功 (或永久失敗) 早於 staleBefore 時重設為待爬取，回傳是否有重設
+// 只有一個呼叫者會拿到 true，避免多位使用者同時收藏時重複排入佇列
+func (r *sqlxPageRepository) ResetIfStale(ctx context.Context, pageID uuid.UUID, staleBefore time.Time) (bool, error) {
+	query := `
+		UPDATE pages SET ` + resetScrapeSet + `, updated_at=$1
+		WHERE id = $2
+		  AND ((scrape_status = 'success' AND scraped_at < $3) OR (scrape_status = 'failed_permanent' AND updated_at < $3))
+	`
+	res, err := r.db.ExecContext(ctx, query, time.Now(), pageID, staleBefore)
+	if err != nil {
+		slog.Error("Failed to reset stale page", "error", err)
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ResetStaleScrapes 將爬取成功時間早於 staleBefore、且仍有人收藏的頁面重設為待爬取，最多 limit 筆，回傳被重設的頁面 ID
+func (r *sqlxPageRepository) ResetStaleScrapes(ctx context.Context, staleBefore time.Time, limit int) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	query := `
+		UPDATE pages SET ` + resetScrapeSet + `, updated_at=$1
+		WHERE id IN (
+			SELECT p.id FROM pages p
+			WHERE p.scrape_status = 'success' AND p.scraped_at < $2
+			  AND EXISTS (SELECT 1 FROM articles a WHERE a.page_id = p.id)
+			ORDER BY p.scraped_at
+			LIMIT $3
+		)
+		RETURNING id
+	`
+	err := r.db.SelectContext(ctx, &ids, query, time.Now(), staleBefore, limit)
+	if err != nil {
+		slog.Error("Failed to reset stale page scrapes", "error", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// ResetScrapesByFilter 依條件批次重設使用者文章所屬頁面的爬取狀態，最多 limit 筆，回傳被重設的頁面 ID
+// 已是待爬取的頁面已在佇列中，與 ResetIfStale 相同不重設，避免重複排入佇列並清除其他使用者共用頁面的重試狀態
+func (r *sqlxPageRepository) ResetScrapesByFilter(ctx context.Context, userEmail string, filter model.RescrapeFilter, limit int) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	// 網域比對使用者提交網址的 host 部分，同時涵蓋子網域 (例如 example.com 也會比對到 blog.example.com)
+	query := `
+		UPDATE pages
+		SET ` + resetScrapeSet + `, updated_at=$1
+		WHERE id IN (
+			SELECT page_id FROM (
+				SELECT a.page_id, p.updated_at, p.scrape_status, ` + urlHost("a.url") + ` AS host
+				FROM articles a
+				JOIN pages p ON p.id = a.page_id
+				WHERE a.user_email = $2
+			) a
+			WHERE a.scrape_status <> 'pending'
+			  AND ($3 = '' OR a.scrape_status = $3)
+			  AND ($4 = '' OR a.host = $4 OR a.host LIKE '%.' || $4)
+			  AND ($5::timestamptz IS NULL OR a.updated_at < $5)
+			ORDER BY a.updated_at
+			LIMIT $6
+		)
+		RETURNING id
+	`
+	err := r.db.SelectContext(ctx, &ids, query, time.Now(), userEmail, filter.Status, strings.ToLower(filter.Domain), filter.OlderThan, limit)
+	if err != nil {
+		slog.Error("Failed to reset page scrapes by filter", "error", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+type pageScore struct {
+	model.Page
+	Score int `db:"score"`
+}
+
+// ListRecommendPages 依使用者標籤的權重 (評分與閱讀狀態)，推薦其他使用者加上標籤、但使用者尚未收藏的頁面
+// 以頁面分組，多位使用者收藏同一網址時會累加分數，而不是各自算成一篇
+func (r *sqlxPageRepository) ListRecommendPages(ctx context.Context, userEmail string) ([]model.Page, error) {
+	// 標籤權重為使用者對加上該標籤的文章的評分加總；未評分的文章以閱讀狀態作為隱性評分：
+	// 加入最愛 5 分、已讀 4 分、未讀就封存 2 分 (收藏了但不想看)，其餘以中間值 3 分計
+	query := `
+        WITH user_tag_weights AS (
+            SELECT t.name AS tag,
+                SUM(COALESCE(r.scores, CASE
+                    WHEN ua.favorited THEN 5
+                    WHEN ua.read_at IS NOT NULL THEN 4
+                    WHEN ua.archived_at IS NOT NULL THEN 2
+                    ELSE 3
+                END)) AS weight
+            FROM tags t
+            JOIN article_tags atg ON atg.tag_id = t.id
+            JOIN articles ua ON ua.id = atg.article_id
+            LEFT JOIN ratings r ON r.article_id = atg.article_id
+            WHERE t.user_email = $1
+            GROUP BY t.name
+        )
+        SELECT ` + prefixColumns("p", pageColumns) + `, COALESCE(SUM(w.weight), 0) AS score
+        FROM pages p
+        JOIN articles a ON a.page_id = p.id
+        JOIN article_tags atg ON atg.article_id = a.id
+        JOIN tags t ON t.id = atg.tag_id
+        LEFT JOIN user_tag_weights w ON w.tag = t.name
+        WHERE p.scrape_status = 'success'
+          AND NOT EXISTS (
+            SELECT 1 FROM articles a2
+            WHERE a2.page_id = p.id
+              AND a2.user_email = $1
+        )
+        GROUP BY p.id
+        ORDER BY score DESC, COUNT(DISTINCT a.user_email) DESC, COUNT(DISTINCT a.id) FILTER (WHERE a.read_at IS NOT NULL) DESC
+        LIMIT 10
+    `
+
+	var pages []pageScore
+	err := r.db.SelectContext(ctx, &pages, query, userEmail)
+	if err != nil {
+		slog.Error("Failed to find recommend pages", "error", err)
+		return nil, err
+	}
+
+	result := make([]model.Page, len(pages))
+	for i, p := range pages {
+		result[i] = p.Page
+	}
+
+	return result, nil
+}
+
+// FindLatestPages 找出其他使用者最近收藏、且使用者尚未收藏的頁面
+func (r *sqlxPageRepository) FindLatestPages(ctx context.Context, userEmail string, limit int) ([]model.Page, error) {
+	var pages []model.Page
+	query := `
+		SELECT ` + prefixColumns("p", pageColumns) + `
+		FROM pages p
+		JOIN (
+			SELECT page_id, MAX(created_at) AS saved_at
+			FROM articles
+			WHERE user_email != $1
+			GROUP BY page_id
+		) s ON s.page_id = p.id
+		WHERE p.scrape_status = 'success'
+		  AND NOT EXISTS (SELECT 1 FROM articles a WHERE a.page_id = p.id AND a.user_email = $1)
+		ORDER BY s.saved_at DESC
+		LIMIT $2
+	`
+	err := r.db.SelectContext(ctx, &pages, query, userEmail, limit)
+	if err != nil {
+		slog.Error("Failed to find latest pages", "error", err)
+		return nil, err
+	}
+
+	return pages, nil
+}
+
+// prefixColumns 為逗號分隔的欄位清單加上資料表別名，用於 JOIN 時避免欄位名稱衝突
+func prefixColumns(alias, columns string) string {
+	fields := strings.Split(columns, ",")
+	for i, f := range fields {
+		fields[i] = alias + "." + strings.TrimSpace(f)
+	}
+
+	return strings.Join(fields, ", ")
+}
@@ -0,0 +1,53 @@
+package sqlximpl
+
+import (
+	"context"
+	"log/slog"
+
+	"deeliai/internal/interfaces"
+	"deeliai/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type sqlxPageContentRepository struct {
+	db *sqlx.DB
+}
+
+func NewPageContentRepository(db *sqlx.DB) interfaces.PageContentRepository {
+	return &sqlxPageContentRepository{db: db}
+}
+
+// Upsert 儲存頁面內文，重新爬取時覆蓋舊的內容
+func (r *sqlxPageContentRepository) Upsert(ctx context.Context, content *model.PageContent) error {
+	query := `
+		INSERT INTO page_contents (page_id, content_html, content_text, word_count, reading_time_minutes, extracted_at)
+		VALUES (:page_id, :content_html, :content_text, :word_count, :reading_time_minutes, now())
+		ON CONFLICT (page_id) DO UPDATE
+		SET content_html = EXCLUDED.content_html,
+			content_text = EXCLUDED.content_text,
+			word_count = EXCLUDED.word_count,
+			reading_time_minutes = EXCLUDED.reading_time_minutes,
+			extracted_at = EXCLUDED.extracted_at
+	`
+	_, err := r.db.NamedExecContext(ctx, query, content)
+	if err != nil {
+		slog.Error("Failed to upsert page content", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// FindByPageID 取得頁面內文，尚未擷取時回傳 sql.ErrNoRows
+func (r *sqlxPageContentRepository) FindByPageID(ctx context.Context, pageID uuid.UUID) (*model.PageContent, error) {
+	content := &model.PageContent{}
+	query := `SELECT * FROM page_contents WHERE page_id = $1`
+	err := r.db.GetContext(ctx, content, query, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
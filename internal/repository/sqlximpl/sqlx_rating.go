@@ -25,23 +25,23 @@ func NewRatingRepository(db *sqlx.DB) interfaces.RatingRepository {
 // CreateOrUpdate 創建或更新使用者的評分
 func (r *sqlxRatingRepository) CreateOrUpdate(ctx context.Context, rating *model.Rating) (*model.Rating, error) {
 	var createdRating model.Rating
-	// 使用 ON CONFLICT DO UPDATE 來處理 upsert (新增或更新)
+	// 使用 ON CONFLICT DO UPDATE 來處理 upsert (新增或更新)；標籤存在 article_tags，由 TagRepository 管理
 	query := `
-		INSERT INTO ratings (user_email, article_id, scores, tags)
+		INSERT INTO ratings AS r (user_email, article_id, scores)
 		SELECT 
 			$1::text,      -- user_email
 			$2::uuid,      -- article_id
-			$3::int,       -- scores
-			$4::text[]     -- tags
+			$3::int        -- scores
 		WHERE EXISTS (
-			SELECT 1 FROM articles 
-			WHERE id = $2::uuid AND user_email = $1::text AND scrape_status = 'success'
+			SELECT 1 FROM articles a
+			JOIN pages p ON p.id = a.page_id
+			WHERE a.id = $2::uuid AND a.user_email = $1::text AND p.scrape_status = 'success'
 		)
 		ON CONFLICT (user_email, article_id) DO UPDATE
 		SET scores = EXCLUDED.scores, updated_at = now()
-		RETURNING id, user_email, article_id, scores, tags, created_at, updated_at
+		RETURNING r.id, r.user_email, r.article_id, r.scores, ` + tagNames("r.article_id") + `, r.created_at, r.updated_at
 	`
-	err := r.db.QueryRowContext(ctx, query, rating.UserEmail, rating.ArticleID, rating.Scores, pq.Array(rating.Tags)).Scan(
+	err := r.db.QueryRowContext(ctx, query, rating.UserEmail, rating.ArticleID, rating.Scores).Scan(
 		&createdRating.ID,
 		&createdRating.UserEmail,
 		&createdRating.ArticleID,
@@ -66,7 +66,7 @@ func (r *sqlxRatingRepository) CreateOrUpdate(ctx context.Context, rating *model
 // FindRatingByUserEmailAndArticleID 取得使用者對單篇文章的評分
 func (r *sqlxRatingRepository) FindRatingByUserEmailAndArticleID(ctx context.Context, userEmail string, articleID uuid.UUID) (*model.Rating, error) {
 	var rating model.Rating
-	query := `SELECT id, user_email, article_id, scores, tags, created_at, updated_at FROM ratings WHERE user_email = $1 AND article_id = $2 LIMIT 1`
+	query := `SELECT r.id, r.user_email, r.article_id, r.scores, ` + tagNames("r.article_id") + `, r.created_at, r.updated_at FROM ratings r WHERE r.user_email = $1 AND r.article_id = $2 LIMIT 1`
 	err := r.db.QueryRowxContext(ctx, query, userEmail, articleID).Scan(
 		&rating.ID,
 		&rating.UserEmail,
@@ -0,0 +1,48 @@
+package sqlximpl
+
+import (
+	"context"
+	"log/slog"
+
+	"deeliai/internal/interfaces"
+	"deeliai/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type sqlxScrapeAttemptRepository struct {
+	db *sqlx.DB
+}
+
+func NewScrapeAttemptRepository(db *sqlx.DB) interfaces.ScrapeAttemptRepository {
+	return &sqlxScrapeAttemptRepository{db: db}
+}
+
+// Create 新增一筆爬取紀錄
+func (r *sqlxScrapeAttemptRepository) Create(ctx context.Context, attempt *model.ScrapeAttempt) error {
+	query := `
+		INSERT INTO scrape_attempts (page_id, success, http_status, error_class, error_message, duration_ms, final_url)
+		VALUES (:page_id, :success, :http_status, :error_class, :error_message, :duration_ms, :final_url)
+	`
+	_, err := r.db.NamedExecContext(ctx, query, attempt)
+	if err != nil {
+		slog.Error("Failed to create scrape attempt", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListByPageID 取得頁面最近的爬取紀錄，新的在前
+func (r *sqlxScrapeAttemptRepository) ListByPageID(ctx context.Context, pageID uuid.UUID, limit int) ([]model.ScrapeAttempt, error) {
+	attempts := []model.ScrapeAttempt{}
+	query := `SELECT * FROM scrape_attempts WHERE page_id = $1 ORDER BY attempted_at DESC LIMIT $2`
+	err := r.db.SelectContext(ctx, &attempts, query, pageID, limit)
+	if err != nil {
+		slog.Error("Failed to list scrape attempts", "error", err)
+		return nil, err
+	}
+
+	return attempts, nil
+}
@@ -0,0 +1,94 @@
+package sqlximpl
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"deeliai/internal/interfaces"
+	"deeliai/internal/model"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type sqlxScrapeJobRepository struct {
+	db *sqlx.DB
+}
+
+func NewScrapeJobRepository(db *sqlx.DB) interfaces.ScrapeJobRepository {
+	return &sqlxScrapeJobRepository{db: db}
+}
+
+// Enqueue 新增一筆任務，若相同任務已在佇列中則不重複新增，只將 generation 加一
+// 任務正在處理中時，處理完的 Ack 會看到 generation 改變而讓任務再被處理一次，不會遺失這次的請求
+func (r *sqlxScrapeJobRepository) Enqueue(ctx context.Context, payload string) error {
+	query := `
+		INSERT INTO scrape_jobs (payload) VALUES ($1)
+		ON CONFLICT (payload) DO UPDATE SET generation = scrape_jobs.generation + 1, updated_at = now()
+	`
+	_, err := r.db.ExecContext(ctx, query, payload)
+	if err != nil {
+		slog.Error("Failed to enqueue scrape job", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// Dequeue 取出可見的任務並在 visibilityTimeout 內對其他 worker 隱藏
+// 使用 FOR UPDATE SKIP LOCKED 讓多個 worker (或多台機器) 可以同時取任務而不互相阻塞
+// 任務若未在期限內 Ack (例如 worker 崩潰)，時間到後會自動重新被取出
+func (r *sqlxScrapeJobRepository) Dequeue(ctx context.Context, workerID string, limit int, visibilityTimeout time.Duration) ([]model.ScrapeJob, error) {
+	var jobs []model.ScrapeJob
+	query := `
+		UPDATE scrape_jobs
+		SET visible_at = $1, attempts = attempts + 1, locked_by = $2, updated_at = $3
+		WHERE id IN (
+			SELECT id FROM scrape_jobs
+			WHERE visible_at <= $3
+			ORDER BY visible_at, id
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+	now := time.Now()
+	err := r.db.SelectContext(ctx, &jobs, query, now.Add(visibilityTimeout), workerID, now, limit)
+	if err != nil {
+		slog.Error("Failed to dequeue scrape jobs", "error", err)
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// Ack 確認任務已處理完畢並將其移出佇列
+// 處理期間任務又被排入 (generation 改變) 時不刪除，改為立即重新可見並重設處理次數
+// 任務已被其他 worker 重新取出 (locked_by 改變) 時不做任何事，回傳 interfaces.ErrScrapeJobLeaseLost
+func (r *sqlxScrapeJobRepository) Ack(ctx context.Context, job *model.ScrapeJob) error {
+	query := `DELETE FROM scrape_jobs WHERE id = $1 AND locked_by = $2 AND generation = $3`
+	res, err := r.db.ExecContext(ctx, query, job.ID, job.LockedBy, job.Generation)
+	if err != nil {
+		slog.Error("Failed to ack scrape job", "error", err)
+		return err
+	}
+	if rowsAffected, err := res.RowsAffected(); err == nil && rowsAffected > 0 {
+		return nil
+	}
+
+	query = `
+		UPDATE scrape_jobs
+		SET visible_at = $1, attempts = 0, locked_by = '', updated_at = $1
+		WHERE id = $2 AND locked_by = $3
+	`
+	res, err = r.db.ExecContext(ctx, query, time.Now(), job.ID, job.LockedBy)
+	if err != nil {
+		slog.Error("Failed to requeue scrape job", "error", err)
+		return err
+	}
+	if rowsAffected, err := res.RowsAffected(); err != nil || rowsAffected == 0 {
+		return interfaces.ErrScrapeJobLeaseLost
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package sqlximpl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"deeliai/internal/interfaces"
+	"deeliai/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxPreviousRefreshTokenHashes 是每個 Session 保留的舊 refresh token 雜湊數量上限
+const maxPreviousRefreshTokenHashes = 100
+
+type sqlxSessionRepository struct {
+	db *sqlx.DB
+}
+
+func NewSessionRepository(db *sqlx.DB) interfaces.SessionRepository {
+	return &sqlxSessionRepository{db: db}
+}
+
+// Create 建立新的登入 Session
+func (r *sqlxSessionRepository) Create(ctx context.Context, session *model.Session) (*model.Session, error) {
+	newSession := &model.Session{}
+	query := `INSERT INTO sessions (user_email, refresh_token_hash, user_agent, ip_address, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING *`
+	err := r.db.QueryRowxContext(ctx, query, session.UserEmail, session.RefreshTokenHash, session.UserAgent, session.IPAddress, session.ExpiresAt).StructScan(newSession)
+	if err != nil {
+		slog.Error("Failed to create session", "error", err)
+		return nil, err
+	}
+
+	return newSession, nil
+}
+
+// FindByID 根據 Session ID 取得 Session
+func (r *sqlxSessionRepository) FindByID(ctx context.Context, sessionID uuid.UUID) (*model.Session, error) {
+	session := &model.Session{}
+	query := `SELECT * FROM sessions WHERE id = $1 LIMIT 1`
+	err := r.db.GetContext(ctx, session, query, sessionID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("Failed to get session by id", "error", err)
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// RotateRefreshToken 以 compare-and-swap 的方式替換 refresh token 雜湊，被換掉的雜湊記到 previous_refresh_token_hashes
+// 只有在舊雜湊相符且 Session 仍有效時才會更新，否則回傳 sql.ErrNoRows
+func (r *sqlxSessionRepository) RotateRefreshToken(ctx context.Context, sessionID uuid.UUID, oldHash, newHash string) (*model.Session, error) {
+	session := &model.Session{}
+	// 只保留最近 maxPreviousRefreshTokenHashes 個，避免長期使用的 Session 無限制地變大
+	query := `
+		UPDATE sessions
+		SET refresh_token_hash = $1,
+		    previous_refresh_token_hashes = (array_prepend(refresh_token_hash::text, previous_refresh_token_hashes))[1:$5],
+		    last_used_at = $2, updated_at = $2
+		WHERE id = $3 AND refresh_token_hash = $4 AND revoked_at IS NULL AND expires_at > $2
+		RETURNING *
+	`
+	err := r.db.QueryRowxContext(ctx, query, newHash, time.Now(), sessionID, oldHash, maxPreviousRefreshTokenHashes).StructScan(session)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("Failed to rotate refresh token", "error", err)
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Revoke 撤銷單一 Session
+func (r *sqlxSessionRepository) Revoke(ctx context.Context, sessionID uuid.UUID) error {
+	query := `UPDATE sessions SET revoked_at = $1, updated_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, time.Now(), sessionID)
+	if err != nil {
+		slog.Error("Failed to revoke session", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// RevokeAllByUserEmail 撤銷使用者所有尚未撤銷的 Session
+func (r *sqlxSessionRepository) RevokeAllByUserEmail(ctx context.Context, userEmail string) error {
+	query := `UPDATE sessions SET revoked_at = $1, updated_at = $1 WHERE user_email = $2 AND revoked_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, time.Now(), userEmail)
+	if err != nil {
+		slog.Error("Failed to revoke sessions by email", "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package sqlximpl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"deeliai/internal/interfaces"
+	"deeliai/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// tagNames 回傳取出文章標籤名稱 (依名稱排序) 的 SQL 運算式
+func tagNames(articleID string) string {
+	return `ARRAY(SELECT t.name FROM article_tags atg JOIN tags t ON t.id = atg.tag_id WHERE atg.article_id = ` + articleID + ` ORDER BY t.name)`
+}
+
+// upsertArticleTags 建立尚不存在的標籤並加到文章上；$1 為使用者、$2 為文章 ID、$3 為標籤名稱
+// ON CONFLICT DO UPDATE 讓已存在的標籤也會被 RETURNING，並等待同時建立相同標籤的交易
+const upsertArticleTags = `
+	tag_ids AS (
+		INSERT INTO tags (user_email, name)
+		SELECT $1, unnest($3::text[])
+		ON CONFLICT (user_email, name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id
+	)
+	INSERT INTO article_tags (article_id, tag_id)
+	SELECT $2, id FROM tag_ids
+	ON CONFLICT DO NOTHING
+`
+
+type sqlxTagRepository struct {
+	db *sqlx.DB
+}
+
+func NewTagRepository(db *sqlx.DB) interfaces.TagRepository {
+	return &sqlxTagRepository{db: db}
+}
+
+// ListByUserEmail 取得使用者所有標籤與各自的文章數，依文章數由多到少排序
+func (r *sqlxTagRepository) ListByUserEmail(ctx context.Context, userEmail string) ([]model.TagCount, error) {
+	tags := []model.TagCount{}
+	query := `
+		SELECT t.id, t.user_email, t.name, t.created_at, COUNT(atg.article_id) AS article_count
+		FROM tags t
+		LEFT JOIN article_tags atg ON atg.tag_id = t.id
+		WHERE t.user_email = $1
+		GROUP BY t.id
+		ORDER BY article_count DESC, t.name
+	`
+	err := r.db.SelectContext(ctx, &tags, query, userEmail)
+	if err != nil {
+		slog.Error("Failed to list tags by email", "error", err)
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// ListByArticleID 取得文章的標籤，依名稱排序
+func (r *sqlxTagRepository) ListByArticleID(ctx context.Context, articleID uuid.UUID) ([]model.Tag, error) {
+	tags := []model.Tag{}
+	query := `
+		SELECT t.id, t.user_email, t.name, t.created_at
+		FROM article_tags atg
+		JOIN tags t ON t.id = atg.tag_id
+		WHERE atg.article_id = $1
+		ORDER BY t.name
+	`
+	err := r.db.SelectContext(ctx, &tags, query, articleID)
+	if err != nil {
+		slog.Error("Failed to list tags by article", "error", err)
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// AddToArticle 為文章加上標籤，尚不存在的標籤會自動建立，文章已有的標籤不受影響
+func (r *sqlxTagRepository) AddToArticle(ctx context.Context, userEmail string, articleID uuid.UUID, names []string) error {
+	_, err := r.db.ExecContext(ctx, `WITH `+upsertArticleTags, userEmail, articleID, pq.Array(names))
+	if err != nil {
+		slog.Error("Failed to add tags to article", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// SetArticleTags 將文章的標籤替換為 names，不在 names 中的標籤會從文章移除 (標籤本身保留)
+func (r *sqlxTagRepository) SetArticleTags(ctx context.Context, userEmail string, articleID uuid.UUID, names []string) error {
+	query := `
+		WITH removed AS (
+			DELETE FROM article_tags atg
+			USING tags t
+			WHERE atg.tag_id = t.id AND atg.article_id = $2 AND t.name <> ALL($3::text[])
+		), ` + upsertArticleTags
+	_, err := r.db.ExecContext(ctx, query, userEmail, articleID, pq.Array(names))
+	if err != nil {
+		slog.Error("Failed to set article tags", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveFromArticle 從文章移除標籤，文章沒有此標籤時回傳 sql.ErrNoRows
+func (r *sqlxTagRepository) RemoveFromArticle(ctx context.Context, articleID, tagID uuid.UUID) error {
+	query := `DELETE FROM article_tags WHERE article_id = $1 AND tag_id = $2`
+	res, err := r.db.ExecContext(ctx, query, articleID, tagID)
+	if err != nil {
+		slog.Error("Failed to remove tag from article", "error", err)
+		return err
+	}
+
+	if rowsAffected, err := res.RowsAffected(); err != nil || rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Rename 修改標籤名稱，標籤不存在或不屬於使用者時回傳 sql.ErrNoRows
+// 使用者已有同名標籤時會合併：文章改掛到既有的標籤上，原標籤刪除，回傳既有的標籤
+func (r *sqlxTagRepository) Rename(ctx context.Context, userEmail string, tagID uuid.UUID, name string) (*model.Tag, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		slog.Error("Failed to begin transaction", "error", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// 鎖住原標籤，避免同時改名或刪除
+	var locked uuid.UUID
+	err = tx.GetContext(ctx, &locked, `SELECT id FROM tags WHERE id = $1 AND user_email = $2 FOR UPDATE`, tagID, userEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &model.Tag{}
+	err = tx.GetContext(ctx, tag, `SELECT id, user_email, name, created_at FROM tags WHERE user_email = $1 AND name = $2 AND id <> $3`, userEmail, name, tagID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		err = tx.GetContext(ctx, tag, `UPDATE tags SET name = $1 WHERE id = $2 RETURNING id, user_email, name, created_at`, name, tagID)
+		if err != nil {
+			slog.Error("Failed to rename tag", "error", err)
+			return nil, err
+		}
+	case err != nil:
+		slog.Error("Failed to find tag by name", "error", err)
+		return nil, err
+	default:
+		merge := `
+			INSERT INTO article_tags (article_id, tag_id, created_at)
+			SELECT article_id, $1, created_at FROM article_tags WHERE tag_id = $2
+			ON CONFLICT DO NOTHING
+		`
+		if _, err := tx.ExecContext(ctx, merge, tag.ID, tagID); err != nil {
+			slog.Error("Failed to merge tags", "error", err)
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, tagID); err != nil {
+			slog.Error("Failed to delete merged tag", "error", err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("Failed to commit tag rename", "error", err)
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// Delete 刪除標籤並從所有文章移除，標籤不存在或不屬於使用者時回傳 sql.ErrNoRows
+func (r *sqlxTagRepository) Delete(ctx context.Context, userEmail string, tagID uuid.UUID) error {
+	query := `DELETE FROM tags WHERE id = $1 AND user_email = $2`
+	res, err := r.db.ExecContext(ctx, query, tagID, userEmail)
+	if err != nil {
+		slog.Error("Failed to delete tag", "error", err)
+		return err
+	}
+
+	if rowsAffected, err := res.RowsAffected(); err != nil || rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
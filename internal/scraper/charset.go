@@ -0,0 +1,30 @@
+package scraper
+
+import (
+	"bytes"
+	"io"
+
+	"golang.org/x/net/html/charset"
+)
+
+// isText 判斷是否需要做編碼轉換；沒有 Content-Type 時當作 HTML 處理
+func isText(mediaType string) bool {
+	return mediaType == "" || mediaType == "text/html" || mediaType == "application/xhtml+xml" || mediaType == "text/plain"
+}
+
+// decodeHTML 將 HTML 或純文字轉為 UTF-8，回傳轉換後的內容與偵測到的編碼名稱
+// 編碼依序由 BOM、Content-Type header 的 charset、<meta charset> (僅 HTML) 判斷，都沒有時依內容猜測
+func decodeHTML(body []byte, contentType string) ([]byte, string, error) {
+	enc, name, _ := charset.DetermineEncoding(body, contentType)
+	if name == "utf-8" {
+		// 去掉 BOM，避免出現在標題開頭
+		return bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")), name, nil
+	}
+
+	decoded, err := io.ReadAll(enc.NewDecoder().Reader(bytes.NewReader(body)))
+	if err != nil {
+		return nil, name, err
+	}
+
+	return decoded, name, nil
+}
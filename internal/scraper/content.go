@@ -0,0 +1,236 @@
+package scraper
+
+import (
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/microcosm-cc/bluemonday"
+	"golang.org/x/net/html"
+)
+
+// wordsPerMinute 與 cjkCharsPerMinute 是估算閱讀時間用的平均閱讀速度
+const (
+	wordsPerMinute    = 230
+	cjkCharsPerMinute = 500
+)
+
+// minContentLength 是判定為文章主體的最短文字長度，過短代表沒有找到內文
+const minContentLength = 140
+
+var (
+	// unlikelyCandidates 與 positiveCandidates 依 class/id 判斷元素是否可能為內文
+	unlikelyCandidates = regexp.MustCompile(`(?i)comment|meta|footer|footnote|sidebar|sponsor|share|social|related|promo|advert|\bads?\b|banner|breadcrumb|menu|nav|popup|cookie|subscribe|newsletter|pagination`)
+	positiveCandidates = regexp.MustCompile(`(?i)article|body|content|entry|main|page|post|story|text|blog`)
+
+	// contentPolicy 只保留閱讀需要的排版標籤，移除 script、style 與事件屬性
+	contentPolicy = newContentPolicy()
+)
+
+// ExtractedContent 是從網頁擷取出的文章主體
+type ExtractedContent struct {
+	HTML               string // 經過 sanitize 的 HTML
+	Text               string // 純文字，段落間以空行分隔
+	WordCount          int
+	ReadingTimeMinutes int
+}
+
+// ExtractContent 以類似 Readability 的方式找出文章主體，找不到足夠長的內文時回傳 nil
+// 會移除 doc 中的 script、nav 等節點，需在 ExtractMetadata 之後呼叫
+func ExtractContent(doc *goquery.Document, pageURL string) *ExtractedContent {
+	doc.Find("script, style, noscript, template, iframe, form, button, input, select, textarea, svg, canvas, nav, header, footer, aside").Remove()
+
+	node := findContentNode(doc)
+	if node == nil {
+		return nil
+	}
+
+	base, _ := url.Parse(pageURL)
+	node.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		s.SetAttr("href", resolveURL(base, s.AttrOr("href", "")))
+	})
+	node.Find("img").Each(func(_ int, s *goquery.Selection) {
+		// lazy load 的圖片真正的網址通常在 data-src
+		src := firstNonEmpty(s.AttrOr("data-src", ""), s.AttrOr("src", ""))
+		s.SetAttr("src", resolveURL(base, src))
+	})
+
+	rawHTML, err := node.Html()
+	if err != nil {
+		return nil
+	}
+
+	text := nodeText(node)
+	if len([]rune(text)) < minContentLength {
+		return nil
+	}
+
+	words := CountWords(text)
+	return &ExtractedContent{
+		HTML:               strings.TrimSpace(contentPolicy.Sanitize(rawHTML)),
+		Text:               text,
+		WordCount:          words,
+		ReadingTimeMinutes: EstimateReadingTime(text),
+	}
+}
+
+// findContentNode 優先使用語意化標籤，否則依段落文字量為各容器評分，取最高分者
+func findContentNode(doc *goquery.Document) *goquery.Selection {
+	for _, selector := range []string{"article", "[itemprop='articleBody']", "[role='main']", "main"} {
+		var best *goquery.Selection
+		bestLength := 0
+		doc.Find(selector).Each(func(_ int, s *goquery.Selection) {
+			if length := len([]rune(strings.TrimSpace(s.Text()))); length > bestLength {
+				best, bestLength = s, length
+			}
+		})
+		if best != nil && bestLength >= minContentLength {
+			return best
+		}
+	}
+
+	scores := make(map[*html.Node]float64)
+	nodes := make(map[*html.Node]*goquery.Selection)
+	score := func(s *goquery.Selection, value float64) {
+		if s.Length() == 0 {
+			return
+		}
+		node := s.Get(0)
+		if _, ok := nodes[node]; !ok {
+			nodes[node] = s
+			scores[node] = classWeight(s)
+		}
+		scores[node] += value
+	}
+
+	doc.Find("p, pre, td").Each(func(_ int, p *goquery.Selection) {
+		text := strings.TrimSpace(p.Text())
+		length := len([]rune(text))
+		if length < 25 {
+			return
+		}
+
+		// 文字越長、逗號越多越像內文，每 100 字最多加 3 分
+		value := 1 + float64(strings.Count(text, ",")+strings.Count(text, "，")) + math.Min(float64(length)/100, 3)
+		score(p.Parent(), value)
+		score(p.Parent().Parent(), value/2)
+	})
+
+	var best *goquery.Selection
+	bestScore := 0.0
+	for node, value := range scores {
+		// 連結比例高的區塊多半是選單或相關文章列表
+		sel := nodes[node]
+		value *= 1 - linkDensity(sel)
+		if value > bestScore {
+			best, bestScore = sel, value
+		}
+	}
+
+	return best
+}
+
+func classWeight(s *goquery.Selection) float64 {
+	weight := 0.0
+	for _, attr := range []string{"class", "id"} {
+		value := s.AttrOr(attr, "")
+		if value == "" {
+			continue
+		}
+		if unlikelyCandidates.MatchString(value) {
+			weight -= 25
+		}
+		if positiveCandidates.MatchString(value) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+func linkDensity(s *goquery.Selection) float64 {
+	textLength := len([]rune(s.Text()))
+	if textLength == 0 {
+		return 0
+	}
+
+	linkLength := 0
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		linkLength += len([]rune(a.Text()))
+	})
+	return float64(linkLength) / float64(textLength)
+}
+
+// nodeText 以區塊元素為單位取出文字，避免段落黏在一起
+func nodeText(s *goquery.Selection) string {
+	var blocks []string
+	s.Find("p, h1, h2, h3, h4, h5, h6, li, pre, blockquote, td").Each(func(_ int, b *goquery.Selection) {
+		// 巢狀的區塊元素只取最內層，避免重複
+		if b.Find("p, li, pre, blockquote").Length() > 0 {
+			return
+		}
+		if text := strings.Join(strings.Fields(b.Text()), " "); text != "" {
+			blocks = append(blocks, text)
+		}
+	})
+
+	if len(blocks) == 0 {
+		return strings.Join(strings.Fields(s.Text()), " ")
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// CountWords 計算字數：中日韓文字每個字算一個字，其他語言以空白分隔的單字計算
+func CountWords(text string) int {
+	cjk, words := countCJKAndWords(text)
+	return cjk + words
+}
+
+// EstimateReadingTime 估算閱讀分鐘數，有內容時至少 1 分鐘
+func EstimateReadingTime(text string) int {
+	cjk, words := countCJKAndWords(text)
+	if cjk+words == 0 {
+		return 0
+	}
+
+	minutes := float64(words)/wordsPerMinute + float64(cjk)/cjkCharsPerMinute
+	return int(math.Max(1, math.Ceil(minutes)))
+}
+
+func countCJKAndWords(text string) (cjk, words int) {
+	inWord := false
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		case r == '\'' || r == '’' || r == '-':
+			// 縮寫與連字號不切斷單字
+		default:
+			inWord = false
+		}
+	}
+	return cjk, words
+}
+
+func newContentPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements("p", "br", "hr", "h1", "h2", "h3", "h4", "h5", "h6",
+		"ul", "ol", "li", "blockquote", "pre", "code", "em", "strong", "b", "i", "u", "s", "sub", "sup",
+		"figure", "figcaption", "table", "thead", "tbody", "tfoot", "tr", "th", "td", "caption")
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowAttrs("src", "alt", "title", "width", "height").OnElements("img")
+	p.AllowAttrs("colspan", "rowspan").OnElements("td", "th")
+	p.AllowURLSchemes("http", "https")
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
@@ -0,0 +1,172 @@
+package scraper
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"deeliai/internal/model"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// maxPreviewRunes 是從 PDF 第一頁或純文字擷取描述的長度上限
+const maxPreviewRunes = 500
+
+// DocumentAcceptTypes 是爬取文章時允許的 Content-Type
+var DocumentAcceptTypes = append([]string{"text/html", "application/xhtml+xml", "application/pdf", "text/plain"}, ImageAcceptTypes...)
+
+var ErrInvalidPDF = errors.New("invalid pdf document")
+
+// DocumentType 依 Content-Type 判斷文件類型；沒有 Content-Type 時當作 HTML
+func DocumentType(mediaType string) string {
+	switch {
+	case mediaType == "application/pdf":
+		return model.DocumentTypePDF
+	case strings.HasPrefix(mediaType, "image/"):
+		return model.DocumentTypeImage
+	case mediaType == "text/plain":
+		return model.DocumentTypeText
+	}
+	return model.DocumentTypeHTML
+}
+
+// ExtractPDF 從 PDF 的 Info 字典取得標題與作者，並擷取第一頁文字作為描述與頁數
+// Info 沒有標題時，依序使用第一頁的第一行與檔名
+func ExtractPDF(page *FetchResult) (meta *model.ArticleMetadata, content *ExtractedContent, err error) {
+	// pdf 套件遇到格式錯誤的檔案可能 panic，不能讓單一檔案拖垮 worker
+	defer func() {
+		if r := recover(); r != nil {
+			meta, content, err = nil, nil, fmt.Errorf("%w: %v", ErrInvalidPDF, r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(page.Body), int64(len(page.Body)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+	}
+
+	info := reader.Trailer().Key("Info")
+	pageCount := reader.NumPage()
+
+	var firstPage string
+	if pageCount > 0 {
+		if text, err := reader.Page(1).GetPlainText(nil); err == nil {
+			firstPage = strings.TrimSpace(text)
+		}
+	}
+
+	meta = &model.ArticleMetadata{
+		Title:       firstNonEmpty(info.Key("Title").Text(), firstLine(firstPage), fileName(page.FinalURL)),
+		Description: truncatePreview(firstPage),
+		Author:      strings.TrimSpace(info.Key("Author").Text()),
+		Keywords:    splitKeywords(info.Key("Keywords").Text()),
+		PublishedAt: parsePDFDate(info.Key("CreationDate").Text()),
+		ModifiedAt:  parsePDFDate(info.Key("ModDate").Text()),
+		Extras: model.Extras{
+			"page_count": pageCount,
+		},
+	}
+	if producer := strings.TrimSpace(info.Key("Producer").Text()); producer != "" {
+		meta.Extras["producer"] = producer
+	}
+
+	if firstPage != "" {
+		content = &ExtractedContent{
+			Text:               firstPage,
+			WordCount:          CountWords(firstPage),
+			ReadingTimeMinutes: EstimateReadingTime(firstPage),
+		}
+	}
+
+	return meta, content, nil
+}
+
+// ExtractImage 以檔名作為圖片的標題，圖片本身就是預覽圖
+func ExtractImage(page *FetchResult) *model.ArticleMetadata {
+	return &model.ArticleMetadata{
+		Title:    fileName(page.FinalURL),
+		ImageURL: page.FinalURL,
+	}
+}
+
+// ExtractText 以第一行作為純文字檔的標題，其後的內容作為描述
+func ExtractText(page *FetchResult) (*model.ArticleMetadata, *ExtractedContent) {
+	text := strings.TrimSpace(strings.ToValidUTF8(string(page.Body), ""))
+	title := firstLine(text)
+
+	meta := &model.ArticleMetadata{
+		Title:       firstNonEmpty(title, fileName(page.FinalURL)),
+		Description: truncatePreview(strings.TrimSpace(strings.TrimPrefix(text, title))),
+	}
+	if text == "" {
+		return meta, nil
+	}
+
+	return meta, &ExtractedContent{
+		Text:               text,
+		WordCount:          CountWords(text),
+		ReadingTimeMinutes: EstimateReadingTime(text),
+	}
+}
+
+// fileName 取出網址路徑最後一段並解碼，例如 /files/Annual%20Report.pdf -> Annual Report.pdf
+func fileName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return u.Hostname()
+	}
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	return name
+}
+
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+func truncatePreview(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= maxPreviewRunes {
+		return text
+	}
+	return string([]rune(text)[:maxPreviewRunes-1]) + "…"
+}
+
+// parsePDFDate 解析 PDF 的日期格式，例如 D:20240102150405+08'00'
+func parsePDFDate(value string) *time.Time {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "D:")
+	if len(value) < 8 {
+		return nil
+	}
+	value = strings.ReplaceAll(value, "'", "")
+
+	for _, layout := range []string{"20060102150405Z0700", "20060102150405Z07", "20060102150405", "200601021504", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	// 時區為 Z 時可能帶有多餘的 00 (例如 Z00'00')
+	if len(value) >= 14 {
+		if t, err := time.Parse("20060102150405", value[:14]); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
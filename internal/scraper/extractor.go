@@ -0,0 +1,107 @@
+package scraper
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"deeliai/internal/model"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// MetadataExtractor 針對特定網站抽取 metadata，可透過 Extras 附加網站特有的資訊
+type MetadataExtractor interface {
+	// Name 用於記錄日誌與存入 extras 的 extractor 欄位
+	Name() string
+	// Match 判斷是否負責處理該網址 (跟隨轉址後的最終網址)
+	Match(u *url.URL) bool
+	// Extract 抽取 metadata；回傳錯誤時 ScrapeService 會改用通用的 extractor
+	Extract(page *FetchResult, doc *goquery.Document) (*model.ArticleMetadata, error)
+}
+
+// GenericExtractor 是預設的 extractor，使用 OpenGraph、Twitter Card、JSON-LD 與一般 HTML 標籤
+type GenericExtractor struct{}
+
+func (GenericExtractor) Name() string { return "generic" }
+
+func (GenericExtractor) Match(*url.URL) bool { return true }
+
+func (GenericExtractor) Extract(page *FetchResult, doc *goquery.Document) (*model.ArticleMetadata, error) {
+	return ExtractMetadata(doc, page.FinalURL), nil
+}
+
+// ExtractorRegistry 依網址挑選 extractor，先註冊的優先，都不符合時使用 fallback
+type ExtractorRegistry struct {
+	mu         sync.RWMutex
+	extractors []MetadataExtractor
+	fallback   MetadataExtractor
+}
+
+func NewExtractorRegistry(extractors ...MetadataExtractor) *ExtractorRegistry {
+	return &ExtractorRegistry{
+		extractors: extractors,
+		fallback:   GenericExtractor{},
+	}
+}
+
+// Register 加入 extractor
+func (r *ExtractorRegistry) Register(e MetadataExtractor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.extractors = append(r.extractors, e)
+}
+
+// For 回傳負責 rawURL 的 extractor
+func (r *ExtractorRegistry) For(rawURL string) MetadataExtractor {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return r.fallback
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, e := range r.extractors {
+		if e.Match(u) {
+			return e
+		}
+	}
+	return r.fallback
+}
+
+// Fallback 回傳通用的 extractor
+func (r *ExtractorRegistry) Fallback() MetadataExtractor {
+	return r.fallback
+}
+
+// MatchHost 判斷 u 的主機是否為 hosts 之一或其子網域，供 extractor 實作 Match 使用
+func MatchHost(u *url.URL, hosts ...string) bool {
+	return matchHost(hosts, u.Hostname())
+}
+
+// MetaContent 取得第一個 attr 屬性等於 name 的 meta 標籤內容
+func MetaContent(doc *goquery.Document, attr, name string) string {
+	return metaContent(doc, attr, name)
+}
+
+// MetaContents 取得所有 attr 屬性等於 name 的 meta 標籤內容
+func MetaContents(doc *goquery.Document, attr, name string) []string {
+	return metaContents(doc, attr, name)
+}
+
+// PathSegments 回傳網址路徑中非空的片段
+func PathSegments(u *url.URL) []string {
+	var segments []string
+	for _, s := range strings.Split(u.Path, "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+// ParseDate 以常見的日期格式解析字串，無法解析時回傳 nil
+func ParseDate(value string) *time.Time {
+	return parseDate(value)
+}
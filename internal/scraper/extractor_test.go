@@ -0,0 +1,84 @@
+package scraper
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"deeliai/internal/model"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// hostExtractor 負責特定主機的測試用 extractor
+type hostExtractor struct {
+	name string
+	host string
+}
+
+func (e hostExtractor) Name() string { return e.name }
+
+func (e hostExtractor) Match(u *url.URL) bool { return MatchHost(u, e.host) }
+
+func (e hostExtractor) Extract(*FetchResult, *goquery.Document) (*model.ArticleMetadata, error) {
+	return &model.ArticleMetadata{}, nil
+}
+
+func TestExtractorRegistryFor(t *testing.T) {
+	registry := NewExtractorRegistry(
+		hostExtractor{name: "first", host: "example.com"},
+		hostExtractor{name: "second", host: "example.com"},
+	)
+	registry.Register(hostExtractor{name: "registered", host: "example.org"})
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://example.com/post", "first"}, // 先註冊的優先
+		{"https://blog.example.com/post", "first"},
+		{"https://example.org/post", "registered"},
+		{"https://notexample.com/post", "generic"},
+		{"https://example.net/post", "generic"},
+		{"://not a url", "generic"},
+	}
+	for _, tt := range tests {
+		if got := registry.For(tt.url).Name(); got != tt.want {
+			t.Errorf("For(%q) = %s, want %s", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestGenericExtractor(t *testing.T) {
+	const html = `<html lang="zh-TW"><head>
+		<title>備用標題</title>
+		<meta property="og:title" content="OpenGraph 標題">
+		<meta name="description" content="頁面描述">
+		<meta property="og:image" content="/images/cover.png">
+	</head><body></body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("parse html: %v", err)
+	}
+
+	registry := NewExtractorRegistry()
+	extractor := registry.Fallback()
+	if !extractor.Match(&url.URL{}) {
+		t.Error("GenericExtractor should match every URL")
+	}
+
+	meta, err := extractor.Extract(&FetchResult{FinalURL: "https://example.com/posts/1"}, doc)
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+	if meta.Title != "OpenGraph 標題" || meta.Description != "頁面描述" {
+		t.Errorf("Title, Description = %q, %q", meta.Title, meta.Description)
+	}
+	if meta.ImageURL != "https://example.com/images/cover.png" {
+		t.Errorf("ImageURL = %q, want it resolved against the final URL", meta.ImageURL)
+	}
+	if meta.Extras != nil {
+		t.Errorf("Extras = %v, want nil", meta.Extras)
+	}
+}
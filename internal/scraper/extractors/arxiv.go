@@ -0,0 +1,55 @@
+package extractors
+
+import (
+	"net/url"
+	"strings"
+
+	"deeliai/internal/model"
+	"deeliai/internal/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ArXiv 處理論文摘要頁，使用 Highwire Press 的 citation_* meta 標籤
+// extras 包含 arXiv ID、作者列表與 PDF 網址
+type ArXiv struct{}
+
+func (ArXiv) Name() string { return "arxiv" }
+
+func (ArXiv) Match(u *url.URL) bool {
+	segments := scraper.PathSegments(u)
+	return scraper.MatchHost(u, "arxiv.org") && len(segments) >= 2 && segments[0] == "abs"
+}
+
+func (a ArXiv) Extract(page *scraper.FetchResult, doc *goquery.Document) (*model.ArticleMetadata, error) {
+	meta := scraper.ExtractMetadata(doc, page.FinalURL)
+
+	if title := scraper.MetaContent(doc, "name", "citation_title"); title != "" {
+		meta.Title = title
+	}
+
+	// 摘要頁的 description 是截斷過的，優先使用完整摘要
+	abstract := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(doc.Find("blockquote.abstract").Text()), "Abstract:"))
+	if abstract == "" {
+		abstract = scraper.MetaContent(doc, "name", "citation_abstract")
+	}
+	if abstract != "" {
+		meta.Description = strings.Join(strings.Fields(abstract), " ")
+	}
+
+	authors := scraper.MetaContents(doc, "name", "citation_author")
+	if len(authors) > 0 {
+		meta.Author = strings.Join(authors, "; ") // citation_author 是「姓, 名」格式，以分號分隔避免混淆
+	}
+	meta.SiteName = "arXiv"
+
+	if published := scraper.ParseDate(strings.ReplaceAll(scraper.MetaContent(doc, "name", "citation_date"), "/", "-")); published != nil {
+		meta.PublishedAt = published
+	}
+
+	return withExtras(meta, a.Name(), map[string]any{
+		"arxiv_id": scraper.MetaContent(doc, "name", "citation_arxiv_id"),
+		"authors":  authors,
+		"pdf_url":  scraper.MetaContent(doc, "name", "citation_pdf_url"),
+	}), nil
+}
@@ -0,0 +1,54 @@
+package extractors
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestArXivExtract(t *testing.T) {
+	meta := extract(t, "arxiv_abs.html", "https://arxiv.org/abs/1706.03762", "arxiv")
+
+	if meta.Title != "Attention Is All You Need" {
+		t.Errorf("Title = %q", meta.Title)
+	}
+	// 使用頁面上完整的摘要，去掉 "Abstract:" 前綴並合併換行
+	wantDescription := "The dominant sequence transduction models are based on complex recurrent or convolutional neural networks in an encoder-decoder configuration. We propose a new simple network architecture, the Transformer, based solely on attention mechanisms."
+	if meta.Description != wantDescription {
+		t.Errorf("Description = %q", meta.Description)
+	}
+	if meta.Author != "Vaswani, Ashish; Shazeer, Noam; Parmar, Niki" {
+		t.Errorf("Author = %q", meta.Author)
+	}
+	if meta.SiteName != "arXiv" {
+		t.Errorf("SiteName = %q", meta.SiteName)
+	}
+	if meta.PublishedAt == nil || !meta.PublishedAt.Equal(time.Date(2017, 6, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("PublishedAt = %v", meta.PublishedAt)
+	}
+
+	if got := meta.Extras["arxiv_id"]; got != "1706.03762" {
+		t.Errorf("extras.arxiv_id = %v", got)
+	}
+	if got := meta.Extras["pdf_url"]; got != "http://arxiv.org/pdf/1706.03762" {
+		t.Errorf("extras.pdf_url = %v", got)
+	}
+	wantAuthors := []string{"Vaswani, Ashish", "Shazeer, Noam", "Parmar, Niki"}
+	if got := meta.Extras["authors"]; !reflect.DeepEqual(got, wantAuthors) {
+		t.Errorf("extras.authors = %v, want %v", got, wantAuthors)
+	}
+}
+
+func TestArXivExtractUsesCitationAbstract(t *testing.T) {
+	// 頁面沒有摘要區塊時，改用 citation_abstract
+	page, doc := loadFixture(t, "arxiv_abs.html", "https://arxiv.org/abs/1706.03762")
+	doc.Find("blockquote.abstract").Remove()
+
+	meta, err := ArXiv{}.Extract(page, doc)
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+	if meta.Description != "The dominant sequence transduction models are based on complex recurrent or convolutional neural networks." {
+		t.Errorf("Description = %q", meta.Description)
+	}
+}
@@ -0,0 +1,96 @@
+// Package extractors 提供針對特定網站的 MetadataExtractor
+// 每個 extractor 先以通用規則抽取 metadata，再補上網站特有的欄位與 extras
+package extractors
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"deeliai/internal/model"
+	"deeliai/internal/scraper"
+)
+
+// NewDefaultRegistry 回傳註冊了所有內建 extractor 的 registry
+func NewDefaultRegistry() *scraper.ExtractorRegistry {
+	return scraper.NewExtractorRegistry(
+		YouTube{},
+		GitHub{},
+		Twitter{},
+		ArXiv{},
+		Medium{},
+	)
+}
+
+// errURLMismatch 表示最終網址不是 extractor 能處理的格式，ScrapeService 會改用通用的 extractor
+var errURLMismatch = errors.New("url does not match extractor")
+
+// withExtras 在 metadata 上附加 extras，並記錄是由哪個 extractor 產生
+func withExtras(meta *model.ArticleMetadata, name string, extras map[string]any) *model.ArticleMetadata {
+	meta.Extras = model.Extras{"extractor": name}
+	for k, v := range extras {
+		// 沒有抓到的值不寫入，避免 extras 中出現大量空值
+		switch t := v.(type) {
+		case string:
+			if t == "" {
+				continue
+			}
+		case []string:
+			if len(t) == 0 {
+				continue
+			}
+		case nil:
+			continue
+		}
+		meta.Extras[k] = v
+	}
+	return meta
+}
+
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// parseISODuration 解析 ISO 8601 的時間長度，例如 PT1H2M3S
+func parseISODuration(value string) (time.Duration, bool) {
+	m := isoDurationPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(value)))
+	if m == nil || value == "P" || value == "PT" {
+		return 0, false
+	}
+
+	var d time.Duration
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(m[i+1], 64)
+		if err != nil {
+			return 0, false
+		}
+		d += time.Duration(n * float64(unit))
+	}
+	return d, true
+}
+
+// parseCount 解析 "1.2k"、"3,456" 這類計數文字
+func parseCount(value string) (int, bool) {
+	value = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), ",", ""))
+	if value == "" {
+		return 0, false
+	}
+
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(value, "k"):
+		multiplier, value = 1e3, strings.TrimSuffix(value, "k")
+	case strings.HasSuffix(value, "m"):
+		multiplier, value = 1e6, strings.TrimSuffix(value, "m")
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int(n * multiplier), true
+}
@@ -0,0 +1,167 @@
+package extractors
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"deeliai/internal/model"
+	"deeliai/internal/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// loadFixture 讀取 testdata 中存下來的 HTML，模擬以 finalURL 爬取到的頁面
+func loadFixture(t *testing.T, name, finalURL string) (*scraper.FetchResult, *goquery.Document) {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture %s: %v", name, err)
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("parse fixture %s: %v", name, err)
+	}
+
+	return &scraper.FetchResult{
+		StatusCode:  200,
+		FinalURL:    finalURL,
+		ContentType: "text/html",
+		Charset:     "utf-8",
+		Body:        body,
+	}, doc
+}
+
+// extract 以 registry 挑選的 extractor 抽取 fixture，並確認挑到的是 wantExtractor
+func extract(t *testing.T, name, finalURL, wantExtractor string) *model.ArticleMetadata {
+	t.Helper()
+
+	page, doc := loadFixture(t, name, finalURL)
+	extractor := NewDefaultRegistry().For(finalURL)
+	if extractor.Name() != wantExtractor {
+		t.Fatalf("For(%q) = %s, want %s", finalURL, extractor.Name(), wantExtractor)
+	}
+
+	meta, err := extractor.Extract(page, doc)
+	if err != nil {
+		t.Fatalf("%s.Extract returned error: %v", wantExtractor, err)
+	}
+	if got := meta.Extras["extractor"]; got != wantExtractor {
+		t.Errorf("extras.extractor = %v, want %s", got, wantExtractor)
+	}
+
+	return meta
+}
+
+func TestDefaultRegistryFor(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://www.youtube.com/watch?v=f6kdp27TYZs", "youtube"},
+		{"https://youtu.be/f6kdp27TYZs", "youtube"},
+		{"https://m.youtube.com/shorts/abc123", "youtube"},
+		{"https://github.com/gin-gonic/gin", "github"},
+		{"https://twitter.com/golang/status/1755662185425338730", "twitter"},
+		{"https://x.com/golang/status/1755662185425338730", "twitter"},
+		{"https://arxiv.org/abs/1706.03762", "arxiv"},
+		{"https://medium.com/better-programming/understanding-go-interfaces-1a2b3c4d5e6f", "medium"},
+		{"https://janedoe.medium.com/some-post-123", "medium"},
+
+		// 不是各網站負責的頁面類型，交給通用 extractor
+		{"https://www.youtube.com/@GoogleDevelopers", "generic"},
+		{"https://github.com/gin-gonic", "generic"},
+		{"https://github.com/gin-gonic/gin/issues/1", "generic"},
+		{"https://x.com/golang", "generic"},
+		{"https://arxiv.org/list/cs.CL/recent", "generic"},
+		{"https://medium.com/", "generic"},
+		{"https://notgithub.com/gin-gonic/gin", "generic"},
+		{"https://go.dev/blog/go1.22", "generic"},
+		{"://not a url", "generic"},
+	}
+
+	registry := NewDefaultRegistry()
+	for _, tt := range tests {
+		if got := registry.For(tt.url).Name(); got != tt.want {
+			t.Errorf("For(%q) = %s, want %s", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestGenericFallback(t *testing.T) {
+	// 沒有專屬 extractor 的網站使用 OpenGraph 等通用規則，不會附加 extras
+	const finalURL = "https://go.dev/blog/go1.22"
+	page, doc := loadFixture(t, "medium_article.html", finalURL)
+
+	registry := NewDefaultRegistry()
+	extractor := registry.For(finalURL)
+	if extractor != registry.Fallback() {
+		t.Fatalf("For(%q) = %s, want the fallback", finalURL, extractor.Name())
+	}
+
+	meta, err := extractor.Extract(page, doc)
+	if err != nil {
+		t.Fatalf("generic Extract returned error: %v", err)
+	}
+	if meta.Title != "Understanding Go Interfaces" {
+		t.Errorf("Title = %q", meta.Title)
+	}
+	if meta.Extras != nil {
+		t.Errorf("Extras = %v, want nil", meta.Extras)
+	}
+}
+
+func TestParseISODuration(t *testing.T) {
+	tests := map[string]int{
+		"PT51M27S":  51*60 + 27,
+		"PT1H2M3S":  3723,
+		"P1DT1S":    86401,
+		"pt30s":     30,
+		"PT1.5S":    1,
+		"PT0S":      0,
+		"P":         -1,
+		"PT":        -1,
+		"51:27":     -1,
+		"":          -1,
+		"PT5M junk": -1,
+	}
+
+	for input, want := range tests {
+		d, ok := parseISODuration(input)
+		if want < 0 {
+			if ok {
+				t.Errorf("parseISODuration(%q) = %v, want failure", input, d)
+			}
+			continue
+		}
+		if !ok || int(d.Seconds()) != want {
+			t.Errorf("parseISODuration(%q) = %v, %v; want %ds", input, d, ok, want)
+		}
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	tests := map[string]int{
+		"81,234": 81234,
+		"8.1k":   8100,
+		"1.2M":   1200000,
+		" 42 ":   42,
+		"":       -1,
+		"lots":   -1,
+	}
+
+	for input, want := range tests {
+		n, ok := parseCount(input)
+		if want < 0 {
+			if ok {
+				t.Errorf("parseCount(%q) = %d, want failure", input, n)
+			}
+			continue
+		}
+		if !ok || n != want {
+			t.Errorf("parseCount(%q) = %d, %v; want %d", input, n, ok, want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package extractors
+
+import (
+	"net/url"
+	"strings"
+
+	"deeliai/internal/model"
+	"deeliai/internal/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// githubReservedPaths 是 github.com 第一層路徑中不是使用者或組織的保留字
+var githubReservedPaths = map[string]bool{
+	"about": true, "collections": true, "enterprise": true, "explore": true, "features": true,
+	"login": true, "marketplace": true, "orgs": true, "pricing": true, "search": true,
+	"settings": true, "sponsors": true, "topics": true, "trending": true,
+}
+
+// GitHub 處理 repo 首頁，extras 包含 owner、repo、星數、fork 數、主要語言與 topics
+type GitHub struct{}
+
+func (GitHub) Name() string { return "github" }
+
+func (GitHub) Match(u *url.URL) bool {
+	segments := scraper.PathSegments(u)
+	return u.Hostname() == "github.com" && len(segments) == 2 && !githubReservedPaths[segments[0]]
+}
+
+func (g GitHub) Extract(page *scraper.FetchResult, doc *goquery.Document) (*model.ArticleMetadata, error) {
+	u, err := url.Parse(page.FinalURL)
+	if err != nil {
+		return nil, err
+	}
+	// 呼叫端可能以轉址前的網址挑選 extractor，最終網址不是 repo 首頁時交由通用規則處理
+	if !g.Match(u) {
+		return nil, errURLMismatch
+	}
+	segments := scraper.PathSegments(u)
+	meta := scraper.ExtractMetadata(doc, page.FinalURL)
+
+	owner, repo := segments[0], segments[1]
+	meta.Author = owner
+	meta.SiteName = "GitHub"
+
+	// og:description 通常是「Contribute to owner/repo development by...」，repo 有描述時改用描述
+	if about := strings.TrimSpace(doc.Find("p.f4.my-3, .BorderGrid-cell p.f4").First().Text()); about != "" {
+		meta.Description = about
+	}
+
+	var topics []string
+	doc.Find("a.topic-tag").Each(func(_ int, s *goquery.Selection) {
+		if topic := strings.TrimSpace(s.Text()); topic != "" {
+			topics = append(topics, topic)
+		}
+	})
+	if len(topics) > 0 {
+		meta.Keywords = topics
+	}
+
+	extras := map[string]any{
+		"owner":    owner,
+		"repo":     repo,
+		"language": strings.TrimSpace(doc.Find("[itemprop='programmingLanguage']").First().Text()),
+		"topics":   topics,
+	}
+	if stars, ok := githubCounter(doc, "#repo-stars-counter-star", "a[href$='/stargazers'] strong"); ok {
+		extras["stars"] = stars
+	}
+	if forks, ok := githubCounter(doc, "#repo-network-counter", "a[href$='/forks'] strong"); ok {
+		extras["forks"] = forks
+	}
+
+	return withExtras(meta, g.Name(), extras), nil
+}
+
+// githubCounter 讀取計數器，title 屬性是完整數字，文字則可能是 1.2k 這種縮寫
+func githubCounter(doc *goquery.Document, selectors ...string) (int, bool) {
+	for _, selector := range selectors {
+		s := doc.Find(selector).First()
+		if s.Length() == 0 {
+			continue
+		}
+		if n, ok := parseCount(s.AttrOr("title", "")); ok {
+			return n, true
+		}
+		if n, ok := parseCount(s.Text()); ok {
+			return n, true
+		}
+	}
+	return 0, false
+}
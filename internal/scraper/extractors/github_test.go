@@ -0,0 +1,89 @@
+package extractors
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestGitHubExtract(t *testing.T) {
+	meta := extract(t, "github_repo.html", "https://github.com/gin-gonic/gin", "github")
+
+	if meta.Author != "gin-gonic" {
+		t.Errorf("Author = %q, want the owner", meta.Author)
+	}
+	if meta.SiteName != "GitHub" {
+		t.Errorf("SiteName = %q", meta.SiteName)
+	}
+	// 以 repo 的 About 取代「Contribute to ...」的 og:description
+	wantDescription := "Gin is a HTTP web framework written in Go (Golang). It features a Martini-like API with much better performance -- up to 40 times faster."
+	if meta.Description != wantDescription {
+		t.Errorf("Description = %q", meta.Description)
+	}
+	wantTopics := []string{"go", "middleware", "framework"}
+	if !reflect.DeepEqual([]string(meta.Keywords), wantTopics) {
+		t.Errorf("Keywords = %v, want %v", meta.Keywords, wantTopics)
+	}
+
+	wantExtras := map[string]any{
+		"owner":    "gin-gonic",
+		"repo":     "gin",
+		"language": "Go",
+		"stars":    81234,
+		"forks":    8117,
+	}
+	for key, want := range wantExtras {
+		if got := meta.Extras[key]; got != want {
+			t.Errorf("extras.%s = %v, want %v", key, got, want)
+		}
+	}
+	if got := meta.Extras["topics"]; !reflect.DeepEqual(got, wantTopics) {
+		t.Errorf("extras.topics = %v, want %v", got, wantTopics)
+	}
+}
+
+func TestGitHubMatch(t *testing.T) {
+	tests := map[string]bool{
+		"https://github.com/gin-gonic/gin":          true,
+		"https://github.com/gin-gonic/gin/":         true,
+		"https://github.com/gin-gonic":              false,
+		"https://github.com/gin-gonic/gin/pulls":    false,
+		"https://gist.github.com/gin-gonic/abc":     false,
+		"https://github.com/topics/go":              false,
+		"https://github.com/orgs/gin-gonic":         false,
+		"https://github.com/settings/profile":       false,
+		"https://github.com/marketplace/actions":    false,
+		"https://github.com/sponsors/gin-gonic":     false,
+		"https://github.com/trending/go":            false,
+		"https://github.com/search?q=gin":           false,
+		"https://github.com/collections/web-server": false,
+	}
+
+	for rawURL, want := range tests {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatalf("parse %q: %v", rawURL, err)
+		}
+		if got := (GitHub{}).Match(u); got != want {
+			t.Errorf("Match(%q) = %v, want %v", rawURL, got, want)
+		}
+	}
+}
+
+func TestGitHubExtractFallsBackAfterRedirectToShorterPath(t *testing.T) {
+	// 以轉址前的 repo 網址挑選 extractor，但 repo 被移除後轉址到 owner 頁面，最終網址只有一段路徑
+	page, doc := loadFixture(t, "github_repo.html", "https://github.com/gin-gonic")
+
+	meta, err := GitHub{}.Extract(page, doc)
+	if err == nil {
+		t.Fatalf("Extract returned %+v, want an error so the generic extractor is used", meta)
+	}
+
+	meta, err = NewDefaultRegistry().Fallback().Extract(page, doc)
+	if err != nil {
+		t.Fatalf("fallback Extract returned error: %v", err)
+	}
+	if meta.SiteName != "GitHub" || meta.Extras != nil {
+		t.Errorf("fallback metadata = %+v", meta)
+	}
+}
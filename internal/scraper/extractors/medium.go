@@ -0,0 +1,45 @@
+package extractors
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"deeliai/internal/model"
+	"deeliai/internal/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var mediumReadingTimePattern = regexp.MustCompile(`(\d+)\s*min read`)
+
+// Medium 處理 Medium 文章 (含自訂子網域的 publication)，extras 包含官方標示的閱讀時間與是否為會員限定
+type Medium struct{}
+
+func (Medium) Name() string { return "medium" }
+
+func (Medium) Match(u *url.URL) bool {
+	return scraper.MatchHost(u, "medium.com") && len(scraper.PathSegments(u)) >= 1
+}
+
+func (m Medium) Extract(page *scraper.FetchResult, doc *goquery.Document) (*model.ArticleMetadata, error) {
+	meta := scraper.ExtractMetadata(doc, page.FinalURL)
+
+	// Medium 的 og:site_name 固定為 Medium，publication 名稱放在 JSON-LD 的 publisher
+	if meta.SiteName == "" {
+		meta.SiteName = "Medium"
+	}
+
+	extras := map[string]any{}
+	// twitter:data1 通常是「5 min read」
+	if match := mediumReadingTimePattern.FindStringSubmatch(scraper.MetaContent(doc, "name", "twitter:data1")); match != nil {
+		if minutes, err := strconv.Atoi(match[1]); err == nil {
+			extras["reading_time_minutes"] = minutes
+		}
+	}
+	if scraper.MetaContent(doc, "property", "article:content_tier") == "locked" {
+		extras["member_only"] = true
+	}
+
+	return withExtras(meta, m.Name(), extras), nil
+}
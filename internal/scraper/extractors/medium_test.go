@@ -0,0 +1,49 @@
+package extractors
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMediumExtract(t *testing.T) {
+	meta := extract(t, "medium_article.html", "https://medium.com/better-programming/understanding-go-interfaces-1a2b3c4d5e6f", "medium")
+
+	if meta.Title != "Understanding Go Interfaces" {
+		t.Errorf("Title = %q", meta.Title)
+	}
+	if meta.Author != "Jane Doe" {
+		t.Errorf("Author = %q", meta.Author)
+	}
+	if meta.SiteName != "Medium" {
+		t.Errorf("SiteName = %q", meta.SiteName)
+	}
+	if meta.PublishedAt == nil || !meta.PublishedAt.Equal(time.Date(2023, 3, 14, 9, 26, 53, 512000000, time.UTC)) {
+		t.Errorf("PublishedAt = %v", meta.PublishedAt)
+	}
+
+	if got := meta.Extras["reading_time_minutes"]; got != 7 {
+		t.Errorf("extras.reading_time_minutes = %v", got)
+	}
+	if got := meta.Extras["member_only"]; got != true {
+		t.Errorf("extras.member_only = %v", got)
+	}
+}
+
+func TestMediumExtractFreeArticle(t *testing.T) {
+	// 非會員限定、沒有標示閱讀時間的文章不寫入對應的 extras
+	page, doc := loadFixture(t, "medium_article.html", "https://janedoe.medium.com/understanding-go-interfaces-1a2b3c4d5e6f")
+	doc.Find("meta[property='article:content_tier'], meta[name='twitter:data1'], meta[property='og:site_name'], meta[name='twitter:site']").Remove()
+
+	meta, err := Medium{}.Extract(page, doc)
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+	if meta.SiteName != "Medium" {
+		t.Errorf("SiteName = %q", meta.SiteName)
+	}
+	for _, key := range []string{"reading_time_minutes", "member_only"} {
+		if _, ok := meta.Extras[key]; ok {
+			t.Errorf("extras.%s is set for a free article without reading time", key)
+		}
+	}
+}
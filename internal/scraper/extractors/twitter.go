@@ -0,0 +1,55 @@
+package extractors
+
+import (
+	"net/url"
+	"strings"
+
+	"deeliai/internal/model"
+	"deeliai/internal/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Twitter 處理 Twitter/X 的貼文頁面，extras 包含貼文 ID 與帳號
+// 貼文頁面大多以 JavaScript 渲染，爬到的 HTML 資訊很少，因此以網址補齊作者並提供 oEmbed 端點
+type Twitter struct{}
+
+func (Twitter) Name() string { return "twitter" }
+
+func (Twitter) Match(u *url.URL) bool {
+	segments := scraper.PathSegments(u)
+	return scraper.MatchHost(u, "twitter.com", "x.com") && len(segments) >= 3 && segments[1] == "status"
+}
+
+func (t Twitter) Extract(page *scraper.FetchResult, doc *goquery.Document) (*model.ArticleMetadata, error) {
+	u, err := url.Parse(page.FinalURL)
+	if err != nil {
+		return nil, err
+	}
+	if !t.Match(u) {
+		return nil, errURLMismatch
+	}
+	segments := scraper.PathSegments(u)
+	meta := scraper.ExtractMetadata(doc, page.FinalURL)
+
+	username, tweetID := segments[0], segments[2]
+	if meta.Author == "" || strings.HasPrefix(meta.Author, "@") {
+		meta.Author = "@" + username
+	}
+	meta.SiteName = "X (Twitter)"
+
+	// 標題常是「X 上的 xxx：「...」」，沒有時以帳號代替
+	if meta.Title == "" {
+		meta.Title = "Post by @" + username
+	}
+
+	canonical := "https://twitter.com/" + username + "/status/" + tweetID
+	if meta.OEmbedURL == "" {
+		meta.OEmbedURL = "https://publish.twitter.com/oembed?url=" + url.QueryEscape(canonical)
+	}
+
+	return withExtras(meta, t.Name(), map[string]any{
+		"tweet_id": tweetID,
+		"username": username,
+	}), nil
+}
@@ -0,0 +1,58 @@
+package extractors
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestTwitterExtract(t *testing.T) {
+	meta := extract(t, "twitter_status.html", "https://x.com/golang/status/1755662185425338730", "twitter")
+
+	if meta.Title != `Go on X: "Go 1.22 is released!"` {
+		t.Errorf("Title = %q", meta.Title)
+	}
+	if meta.Author != "@golang" {
+		t.Errorf("Author = %q", meta.Author)
+	}
+	if meta.SiteName != "X (Twitter)" {
+		t.Errorf("SiteName = %q", meta.SiteName)
+	}
+	// oEmbed 端點一律使用 twitter.com 網址
+	wantOEmbed := "https://publish.twitter.com/oembed?url=" + url.QueryEscape("https://twitter.com/golang/status/1755662185425338730")
+	if meta.OEmbedURL != wantOEmbed {
+		t.Errorf("OEmbedURL = %q, want %q", meta.OEmbedURL, wantOEmbed)
+	}
+
+	if got := meta.Extras["tweet_id"]; got != "1755662185425338730" {
+		t.Errorf("extras.tweet_id = %v", got)
+	}
+	if got := meta.Extras["username"]; got != "golang" {
+		t.Errorf("extras.username = %v", got)
+	}
+}
+
+func TestTwitterExtractWithoutMetadata(t *testing.T) {
+	// 以 JavaScript 渲染、沒有任何 meta 標籤的頁面以網址補齊標題與作者
+	page, doc := loadFixture(t, "twitter_status.html", "https://twitter.com/golang/status/42")
+	doc.Find("meta, title").Remove()
+
+	meta, err := Twitter{}.Extract(page, doc)
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+	if meta.Title != "Post by @golang" {
+		t.Errorf("Title = %q", meta.Title)
+	}
+	if meta.Author != "@golang" {
+		t.Errorf("Author = %q", meta.Author)
+	}
+}
+
+func TestTwitterExtractRejectsNonStatusURL(t *testing.T) {
+	// 貼文被刪除後轉址到帳號頁面，交由通用 extractor 處理
+	page, doc := loadFixture(t, "twitter_status.html", "https://x.com/golang")
+
+	if _, err := (Twitter{}).Extract(page, doc); err == nil {
+		t.Fatal("Extract on a profile page returned no error")
+	}
+}
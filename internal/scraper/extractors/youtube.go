@@ -0,0 +1,94 @@
+package extractors
+
+import (
+	"net/url"
+	"strings"
+
+	"deeliai/internal/model"
+	"deeliai/internal/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// YouTube 處理影片頁面，extras 包含影片 ID、長度 (秒) 與頻道
+type YouTube struct{}
+
+func (YouTube) Name() string { return "youtube" }
+
+func (YouTube) Match(u *url.URL) bool {
+	return youtubeVideoID(u) != ""
+}
+
+func (y YouTube) Extract(page *scraper.FetchResult, doc *goquery.Document) (*model.ArticleMetadata, error) {
+	u, err := url.Parse(page.FinalURL)
+	if err != nil {
+		return nil, err
+	}
+	videoID := youtubeVideoID(u)
+	if videoID == "" {
+		return nil, errURLMismatch
+	}
+	meta := scraper.ExtractMetadata(doc, page.FinalURL)
+
+	channel := firstNonEmpty(
+		doc.Find("span[itemprop='author'] link[itemprop='name']").AttrOr("content", ""),
+		doc.Find("link[itemprop='name']").AttrOr("content", ""),
+	)
+	if channel != "" {
+		meta.Author = channel
+	}
+	meta.SiteName = "YouTube"
+
+	// 影片頁的 og:image 偶爾缺少，改用固定格式的縮圖網址
+	if meta.ImageURL == "" {
+		meta.ImageURL = "https://i.ytimg.com/vi/" + videoID + "/hqdefault.jpg"
+	}
+	if meta.OEmbedURL == "" {
+		meta.OEmbedURL = "https://www.youtube.com/oembed?format=json&url=" + url.QueryEscape("https://www.youtube.com/watch?v="+videoID)
+	}
+
+	extras := map[string]any{
+		"video_id": videoID,
+		"channel":  channel,
+	}
+	if d, ok := parseISODuration(scraper.MetaContent(doc, "itemprop", "duration")); ok {
+		extras["duration_seconds"] = int(d.Seconds())
+	}
+	if meta.PublishedAt == nil {
+		meta.PublishedAt = scraper.ParseDate(firstNonEmpty(
+			scraper.MetaContent(doc, "itemprop", "uploadDate"),
+			scraper.MetaContent(doc, "itemprop", "datePublished"),
+		))
+	}
+
+	return withExtras(meta, y.Name(), extras), nil
+}
+
+// youtubeVideoID 從 watch、youtu.be、shorts 與 embed 網址取出影片 ID
+func youtubeVideoID(u *url.URL) string {
+	segments := scraper.PathSegments(u)
+
+	switch {
+	case scraper.MatchHost(u, "youtu.be"):
+		if len(segments) > 0 {
+			return segments[0]
+		}
+	case scraper.MatchHost(u, "youtube.com"):
+		if len(segments) == 1 && segments[0] == "watch" {
+			return u.Query().Get("v")
+		}
+		if len(segments) == 2 && (segments[0] == "shorts" || segments[0] == "embed" || segments[0] == "live") {
+			return segments[1]
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package extractors
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestYouTubeExtract(t *testing.T) {
+	meta := extract(t, "youtube_watch.html", "https://www.youtube.com/watch?v=f6kdp27TYZs", "youtube")
+
+	if meta.Title != "Google I/O 2012 - Go Concurrency Patterns" {
+		t.Errorf("Title = %q", meta.Title)
+	}
+	if meta.Author != "Google for Developers" {
+		t.Errorf("Author = %q, want the channel name", meta.Author)
+	}
+	if meta.SiteName != "YouTube" {
+		t.Errorf("SiteName = %q", meta.SiteName)
+	}
+	if meta.ImageURL != "https://i.ytimg.com/vi/f6kdp27TYZs/maxresdefault.jpg" {
+		t.Errorf("ImageURL = %q", meta.ImageURL)
+	}
+	if meta.PublishedAt == nil || !meta.PublishedAt.Equal(time.Date(2012, 7, 2, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("PublishedAt = %v", meta.PublishedAt)
+	}
+
+	if got := meta.Extras["video_id"]; got != "f6kdp27TYZs" {
+		t.Errorf("extras.video_id = %v", got)
+	}
+	if got := meta.Extras["channel"]; got != "Google for Developers" {
+		t.Errorf("extras.channel = %v", got)
+	}
+	if got := meta.Extras["duration_seconds"]; got != 51*60+27 {
+		t.Errorf("extras.duration_seconds = %v", got)
+	}
+}
+
+func TestYouTubeExtractFillsMissingImageAndOEmbed(t *testing.T) {
+	// 短網址頁面缺少 og:image 與 oEmbed link 時，以影片 ID 組出固定格式的網址
+	page, doc := loadFixture(t, "youtube_watch.html", "https://youtu.be/abc123")
+	doc.Find("meta[property='og:image'], link[type='application/json+oembed']").Remove()
+
+	meta, err := YouTube{}.Extract(page, doc)
+	if err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+	if meta.ImageURL != "https://i.ytimg.com/vi/abc123/hqdefault.jpg" {
+		t.Errorf("ImageURL = %q", meta.ImageURL)
+	}
+	want := "https://www.youtube.com/oembed?format=json&url=" + url.QueryEscape("https://www.youtube.com/watch?v=abc123")
+	if meta.OEmbedURL != want {
+		t.Errorf("OEmbedURL = %q, want %q", meta.OEmbedURL, want)
+	}
+}
+
+func TestYouTubeExtractRejectsNonVideoURL(t *testing.T) {
+	// 影片被轉址到頻道頁時沒有影片 ID，交由通用 extractor 處理
+	page, doc := loadFixture(t, "youtube_watch.html", "https://www.youtube.com/@GoogleDevelopers")
+
+	if _, err := (YouTube{}).Extract(page, doc); err == nil {
+		t.Fatal("Extract on a channel page returned no error")
+	}
+}
@@ -0,0 +1,174 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBodyTooLarge           = errors.New("response body exceeds size limit")
+	ErrTooManyRedirects       = errors.New("too many redirects")
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+)
+
+// FetcherOptions 設定爬取用 HTTP client 的各項限制，零值欄位使用預設值
+type FetcherOptions struct {
+	ConnectTimeout time.Duration // 建立 TCP 連線與 TLS 握手的逾時
+	ReadTimeout    time.Duration // 從送出請求到讀完 body 的總逾時
+	MaxBodyBytes   int64         // body 大小上限，超過視為失敗
+	MaxRedirects   int           // 轉址次數上限
+	UserAgent      string
+	AcceptTypes    []string  // 允許的 Content-Type (media type)
+	Guard          *URLGuard // 目標位址檢查，nil 時使用預設規則 (拒絕內網與保留位址)
+}
+
+// DefaultFetcherOptions 是未設定時使用的預設值
+var DefaultFetcherOptions = FetcherOptions{
+	ConnectTimeout: 5 * time.Second,
+	ReadTimeout:    15 * time.Second,
+	MaxBodyBytes:   20 << 20, // 20 MiB，PDF 論文常超過 5 MiB
+	MaxRedirects:   5,
+	UserAgent:      "DeeliaiBot/1.0 (+https://deeli.ai)",
+	AcceptTypes:    []string{"text/html", "application/xhtml+xml"},
+}
+
+// FetchResult 是單次抓取的結果
+// 非 2xx 回應不視為錯誤，由呼叫端依 StatusCode 判斷，此時 Body 為空
+type FetchResult struct {
+	StatusCode  int
+	Status      string
+	Header      http.Header
+	FinalURL    string // 跟隨轉址後的最終 URL
+	ContentType string // 不含參數的 media type
+	Charset     string // HTML 與純文字原始的字元編碼，Body 已轉為 UTF-8
+	Body        []byte
+}
+
+// Fetcher 負責以受限的 HTTP client 抓取網頁，避免單一緩慢或過大的頁面卡住 worker
+type Fetcher struct {
+	client *http.Client
+	opts   FetcherOptions
+}
+
+func NewFetcher(opts FetcherOptions) *Fetcher {
+	if opts.ConnectTimeout <= 0 {
+		opts.ConnectTimeout = DefaultFetcherOptions.ConnectTimeout
+	}
+	if opts.ReadTimeout <= 0 {
+		opts.ReadTimeout = DefaultFetcherOptions.ReadTimeout
+	}
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultFetcherOptions.MaxBodyBytes
+	}
+	if opts.MaxRedirects <= 0 {
+		opts.MaxRedirects = DefaultFetcherOptions.MaxRedirects
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultFetcherOptions.UserAgent
+	}
+	if len(opts.AcceptTypes) == 0 {
+		opts.AcceptTypes = DefaultFetcherOptions.AcceptTypes
+	}
+	if opts.Guard == nil {
+		opts.Guard, _ = NewURLGuard(nil, nil)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   opts.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	// 不使用環境變數的 proxy，否則實際連線的是 proxy，Guard 無法檢查目標位址
+	transport := &http.Transport{
+		DialContext:           opts.Guard.DialContext(dialer),
+		TLSHandshakeTimeout:   opts.ConnectTimeout,
+		ResponseHeaderTimeout: opts.ReadTimeout,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+	}
+
+	maxRedirects := opts.MaxRedirects
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   opts.ReadTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return ErrTooManyRedirects
+			}
+			// 轉址目標的位址會在建立連線時由 Guard 檢查，這裡只需擋下非 http(s) 的 scheme
+			return checkScheme(req.URL)
+		},
+	}
+
+	return &Fetcher{client: client, opts: opts}
+}
+
+// Fetch 抓取 rawURL，請求會隨 ctx 取消
+// accept 可覆蓋允許的 Content-Type (例如下載圖片時)，未指定時使用 FetcherOptions.AcceptTypes
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string, accept ...string) (*FetchResult, error) {
+	if len(accept) == 0 {
+		accept = f.opts.AcceptTypes
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScheme(req.URL); err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.opts.UserAgent)
+	req.Header.Set("Accept", strings.Join(accept, ",")+";q=0.9,*/*;q=0.8")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &FetchResult{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+		FinalURL:   resp.Request.URL.String(),
+	}
+	result.ContentType, _, _ = mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return result, nil
+	}
+
+	// 沒有 Content-Type 時交給解析器嘗試
+	if result.ContentType != "" && !slices.Contains(accept, result.ContentType) {
+		return result, fmt.Errorf("%w: %s", ErrUnsupportedContentType, result.ContentType)
+	}
+
+	// 先以 Content-Length 快速拒絕，再以 LimitReader 防止 header 不實
+	if resp.ContentLength > f.opts.MaxBodyBytes {
+		return result, fmt.Errorf("%w: %d bytes", ErrBodyTooLarge, resp.ContentLength)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.opts.MaxBodyBytes+1))
+	if err != nil {
+		return result, err
+	}
+	if int64(len(body)) > f.opts.MaxBodyBytes {
+		return result, fmt.Errorf("%w: more than %d bytes", ErrBodyTooLarge, f.opts.MaxBodyBytes)
+	}
+	result.Body = body
+
+	// HTML 與純文字統一轉為 UTF-8，避免 Big5、GBK、Shift_JIS 等舊編碼的頁面變成亂碼
+	if isText(result.ContentType) && (result.ContentType != "" || slices.Contains(accept, "text/html")) {
+		if result.Body, result.Charset, err = decodeHTML(body, resp.Header.Get("Content-Type")); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
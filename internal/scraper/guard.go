@@ -0,0 +1,227 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrUnsupportedScheme = errors.New("only http and https urls are allowed")
+	ErrBlockedAddress    = errors.New("destination address is not allowed")
+)
+
+// blockedPrefixes 是 netip 內建判斷以外、同樣不應對外連線的保留網段
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // 本網路
+	netip.MustParsePrefix("100.64.0.0/10"),   // CGNAT，部分雲端的 metadata 服務位於此
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF 協定保留
+	netip.MustParsePrefix("198.18.0.0/15"),   // 效能測試
+	netip.MustParsePrefix("240.0.0.0/4"),     // 保留 (含廣播位址)
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64，可藉此連到內網 IPv4
+	netip.MustParsePrefix("2002::/16"),       // 6to4
+	netip.MustParsePrefix("fec0::/10"),       // 已廢棄的 site-local
+	netip.MustParsePrefix("100::/64"),        // discard-only
+	netip.MustParsePrefix("2001:db8::/32"),   // 文件範例
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay anycast
+	netip.MustParsePrefix("198.51.100.0/24"), // 文件範例
+	netip.MustParsePrefix("203.0.113.0/24"),  // 文件範例
+	netip.MustParsePrefix("192.0.2.0/24"),    // 文件範例
+}
+
+// URLGuard 防止爬蟲被當作 SSRF 跳板：只允許 http/https，並拒絕連到內網、loopback、link-local 與雲端 metadata 等位址
+// 檢查發生在實際建立連線時，轉址後的目標與 DNS rebinding 都會被攔下
+type URLGuard struct {
+	allowPrefixes []netip.Prefix
+	denyPrefixes  []netip.Prefix
+	allowHosts    []string
+	denyHosts     []string
+	resolver      *net.Resolver
+}
+
+// NewURLGuard 建立 URLGuard，allow 與 deny 的項目可以是 IP、CIDR 或主機名稱 (同時比對子網域)
+// deny 優先於 allow；allow 可用來開放特定的內網位址或主機
+func NewURLGuard(allow, deny []string) (*URLGuard, error) {
+	g := &URLGuard{resolver: net.DefaultResolver}
+
+	var err error
+	if g.allowPrefixes, g.allowHosts, err = parseGuardEntries(allow); err != nil {
+		return nil, err
+	}
+	if g.denyPrefixes, g.denyHosts, err = parseGuardEntries(deny); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// CheckURL 在提交文章時先行檢查 URL；DNS 解析失敗不視為違規，留給爬取時處理
+func (g *URLGuard) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if err := checkScheme(u); err != nil {
+		return err
+	}
+
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return g.checkAddr(host, addr)
+	}
+	if g.hostDenied(host) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+
+	addrs, err := g.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if err := g.checkAddr(host, addr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DialContext 包裝 dialer：自行解析 DNS 並檢查所有位址，再直接連到檢查過的 IP
+// 因為連線的 IP 就是檢查過的 IP，解析結果在檢查後被竄改 (DNS rebinding) 也無法繞過
+func (g *URLGuard) DialContext(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		var addrs []netip.Addr
+		if addr, err := netip.ParseAddr(host); err == nil {
+			addrs = []netip.Addr{addr}
+		} else {
+			if g.hostDenied(host) {
+				return nil, fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+			}
+			if addrs, err = g.resolver.LookupNetIP(ctx, "ip", host); err != nil {
+				return nil, err
+			}
+		}
+
+		// 只要有任一位址被拒絕就整個拒絕，避免攻擊者混入公開位址
+		for _, addr := range addrs {
+			if err := g.checkAddr(host, addr); err != nil {
+				return nil, err
+			}
+		}
+
+		var lastErr error
+		for _, addr := range addrs {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr.Unmap().String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+
+		return nil, lastErr
+	}
+}
+
+func (g *URLGuard) checkAddr(host string, addr netip.Addr) error {
+	addr = addr.Unmap().WithZone("")
+
+	for _, p := range g.denyPrefixes {
+		if p.Contains(addr) {
+			return fmt.Errorf("%w: %s", ErrBlockedAddress, addr)
+		}
+	}
+	if g.hostAllowed(host) {
+		return nil
+	}
+	for _, p := range g.allowPrefixes {
+		if p.Contains(addr) {
+			return nil
+		}
+	}
+
+	if isReservedAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, addr)
+	}
+
+	return nil
+}
+
+func (g *URLGuard) hostDenied(host string) bool {
+	return matchHost(g.denyHosts, host)
+}
+
+func (g *URLGuard) hostAllowed(host string) bool {
+	return matchHost(g.allowHosts, host)
+}
+
+func isReservedAddr(addr netip.Addr) bool {
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return true
+	}
+
+	for _, p := range blockedPrefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: %q", ErrUnsupportedScheme, u.Scheme)
+	}
+	return nil
+}
+
+// matchHost 比對主機名稱本身或其子網域
+func matchHost(hosts []string, host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, h := range hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseGuardEntries(entries []string) ([]netip.Prefix, []string, error) {
+	var prefixes []netip.Prefix
+	var hosts []string
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			p, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid cidr %q: %w", entry, err)
+			}
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		hosts = append(hosts, strings.TrimSuffix(strings.ToLower(entry), "."))
+	}
+
+	return prefixes, hosts, nil
+}
@@ -0,0 +1,191 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestURLGuardCheckURLDefaultRules(t *testing.T) {
+	guard, err := NewURLGuard(nil, nil)
+	if err != nil {
+		t.Fatalf("NewURLGuard returned error: %v", err)
+	}
+
+	tests := []struct {
+		url     string
+		blocked bool
+	}{
+		// loopback 與未指定位址
+		{"http://127.0.0.1/", true},
+		{"http://127.1.2.3:8080/", true},
+		{"http://[::1]/", true},
+		{"http://0.0.0.0/", true},
+		{"http://[::]/", true},
+		// RFC 1918 與 IPv6 ULA
+		{"http://10.0.0.1/", true},
+		{"http://172.16.5.4/", true},
+		{"http://172.31.255.255/", true},
+		{"http://192.168.1.1/", true},
+		{"http://[fd00::1]/", true},
+		// link-local 與雲端 metadata
+		{"http://169.254.169.254/latest/meta-data/", true},
+		{"http://[fe80::1]/", true},
+		{"http://[fe80::1%25eth0]/", true},
+		{"http://100.100.100.200/", true}, // CGNAT 網段的 metadata 服務
+		// 以 IPv6 包裝的 IPv4 位址
+		{"http://[::ffff:127.0.0.1]/", true},
+		{"http://[::ffff:10.0.0.1]/", true},
+		{"http://[::ffff:169.254.169.254]/", true},
+		{"http://[64:ff9b::a00:1]/", true},   // NAT64 10.0.0.1
+		{"http://[64:ff9b::808:808]/", true}, // NAT64 一律拒絕，無法確定實際連到的 IPv4
+		{"http://[2002:a00:1::]/", true},     // 6to4 10.0.0.1
+		// 其他保留網段
+		{"http://224.0.0.1/", true},
+		{"http://255.255.255.255/", true},
+		{"http://192.0.2.10/", true},
+		// 公開位址
+		{"http://8.8.8.8/", false},
+		{"https://1.1.1.1:8443/", false},
+		{"http://172.32.0.1/", false},
+		{"http://[2606:4700:4700::1111]/", false},
+		{"http://[::ffff:8.8.8.8]/", false},
+	}
+	for _, tt := range tests {
+		err := guard.CheckURL(context.Background(), tt.url)
+		if tt.blocked && !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("CheckURL(%q) = %v, want ErrBlockedAddress", tt.url, err)
+		}
+		if !tt.blocked && err != nil {
+			t.Errorf("CheckURL(%q) = %v, want nil", tt.url, err)
+		}
+	}
+}
+
+func TestURLGuardCheckURLScheme(t *testing.T) {
+	guard, _ := NewURLGuard(nil, nil)
+
+	for _, rawURL := range []string{"ftp://example.com/a", "file:///etc/passwd", "gopher://127.0.0.1:6379/_INFO", "javascript:alert(1)"} {
+		if err := guard.CheckURL(context.Background(), rawURL); !errors.Is(err, ErrUnsupportedScheme) {
+			t.Errorf("CheckURL(%q) = %v, want ErrUnsupportedScheme", rawURL, err)
+		}
+	}
+}
+
+func TestURLGuardAllowAndDeny(t *testing.T) {
+	guard, err := NewURLGuard(
+		[]string{"10.1.0.0/16", "192.168.1.10", "intranet.example"},
+		[]string{"10.1.2.0/24", "8.8.8.8", "Evil.Example."},
+	)
+	if err != nil {
+		t.Fatalf("NewURLGuard returned error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		host    string
+		addr    string
+		blocked bool
+	}{
+		{"allowed cidr", "10.1.5.5", "10.1.5.5", false},
+		{"allowed ip", "192.168.1.10", "192.168.1.10", false},
+		{"allowed ip mapped to ipv6", "::ffff:192.168.1.10", "::ffff:192.168.1.10", false},
+		{"private outside allow list", "192.168.1.11", "192.168.1.11", true},
+		{"deny wins over allowed cidr", "10.1.2.3", "10.1.2.3", true},
+		{"deny applies to public ip", "8.8.8.8", "8.8.8.8", true},
+		{"deny applies to ip mapped to ipv6", "::ffff:8.8.8.8", "::ffff:8.8.8.8", true},
+		{"deny cidr applies to ip mapped to ipv6", "::ffff:10.1.2.3", "::ffff:10.1.2.3", true},
+		{"allowed host resolving to private ip", "intranet.example", "10.9.9.9", false},
+		{"allowed subdomain", "wiki.intranet.example", "10.9.9.9", false},
+		{"deny wins over allowed host", "intranet.example", "10.1.2.3", true},
+		{"other host resolving to private ip", "other.example", "10.9.9.9", true},
+	}
+	for _, tt := range tests {
+		err := guard.checkAddr(tt.host, netip.MustParseAddr(tt.addr))
+		if tt.blocked && !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("%s: checkAddr(%q, %s) = %v, want ErrBlockedAddress", tt.name, tt.host, tt.addr, err)
+		}
+		if !tt.blocked && err != nil {
+			t.Errorf("%s: checkAddr(%q, %s) = %v, want nil", tt.name, tt.host, tt.addr, err)
+		}
+	}
+
+	// 拒絕的主機名稱 (含子網域) 不需解析 DNS 就會被擋下
+	for _, rawURL := range []string{"http://evil.example/", "http://api.EVIL.example./"} {
+		if err := guard.CheckURL(context.Background(), rawURL); !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("CheckURL(%q) = %v, want ErrBlockedAddress", rawURL, err)
+		}
+	}
+
+	if _, err := NewURLGuard([]string{"10.0.0.0/33"}, nil); err == nil {
+		t.Error("NewURLGuard accepted an invalid cidr")
+	}
+}
+
+func TestURLGuardDialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+	dialer := &net.Dialer{Timeout: time.Second}
+
+	guard, _ := NewURLGuard(nil, nil)
+	if _, err := guard.DialContext(dialer)(context.Background(), "tcp", address); !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("dial %s = %v, want ErrBlockedAddress", address, err)
+	}
+
+	allowed, _ := NewURLGuard([]string{"127.0.0.1"}, nil)
+	conn, err := allowed.DialContext(dialer)(context.Background(), "tcp", address)
+	if err != nil {
+		t.Fatalf("dial %s with 127.0.0.1 allowed returned error: %v", address, err)
+	}
+	conn.Close()
+}
+
+func TestFetcherBlocksRedirectToPrivateAddress(t *testing.T) {
+	targets := []string{
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/",
+		"http://[::ffff:127.0.0.2]/",
+		"http://127.0.0.2/", // 與測試伺服器同屬 loopback，但不在允許清單中
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ok" {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+			return
+		}
+		http.Redirect(w, r, r.URL.Query().Get("to"), http.StatusFound)
+	}))
+	defer server.Close()
+
+	// 只開放測試伺服器本身的位址，模擬一個公開網站轉址到內網
+	guard, _ := NewURLGuard([]string{"127.0.0.1"}, nil)
+	fetcher := NewFetcher(FetcherOptions{Guard: guard})
+
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/ok"); err != nil {
+		t.Fatalf("Fetch of the allowed server returned error: %v", err)
+	}
+
+	for _, target := range targets {
+		_, err := fetcher.Fetch(context.Background(), server.URL+"/?to="+target)
+		if !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("Fetch redirecting to %s = %v, want ErrBlockedAddress", target, err)
+		}
+	}
+
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/?to=ftp://example.com/a"); !errors.Is(err, ErrUnsupportedScheme) {
+		t.Errorf("Fetch redirecting to ftp = %v, want ErrUnsupportedScheme", err)
+	}
+
+	// 預設規則下連測試伺服器本身 (loopback) 也會被擋下
+	if _, err := NewFetcher(FetcherOptions{}).Fetch(context.Background(), server.URL+"/ok"); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Fetch of a loopback server with default rules = %v, want ErrBlockedAddress", err)
+	}
+}
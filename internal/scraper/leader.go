@@ -0,0 +1,66 @@
+package scraper
+
+import (
+	"context"
+	"deeliai/internal/interfaces"
+	"log"
+	"time"
+)
+
+// RunAsLeader 在多個實例之間選出一個 leader 執行 run
+// 未取得鎖的實例每隔 interval 重新嘗試；leader 失去鎖時會取消 run 的 context 並重新參與選舉
+func RunAsLeader(ctx context.Context, lock interfaces.LeaderLock, interval time.Duration, run func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		acquired, err := lock.TryAcquire(ctx)
+		if err != nil {
+			log.Printf("Leader election failed: %v", err)
+		}
+
+		if acquired {
+			log.Println("Acquired leader lock")
+			lead(ctx, lock, ticker, run)
+
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := lock.Release(releaseCtx); err != nil {
+				log.Printf("Failed to release leader lock: %v", err)
+			}
+			cancel()
+			log.Println("Released leader lock")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead 執行 run 並定期確認鎖仍然持有，直到 ctx 結束或失去鎖
+func lead(ctx context.Context, lock interfaces.LeaderLock, ticker *time.Ticker, run func(ctx context.Context)) {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run(leaderCtx)
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := lock.Check(leaderCtx); err != nil {
+				log.Printf("Lost leader lock: %v", err)
+				cancel()
+				<-done
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package scraper
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"deeliai/internal/model"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// jsonLDArticleTypes 是會被視為文章的 schema.org 類型
+var jsonLDArticleTypes = map[string]bool{
+	"Article":     true,
+	"NewsArticle": true,
+	"BlogPosting": true,
+}
+
+// dateLayouts 是 meta 標籤與 JSON-LD 常見的日期格式
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// ExtractMetadata 從 HTML 文件中抽取文章 metadata，pageURL 用於將相對網址轉為絕對網址
+// 同一欄位有多個來源時，優先順序為 OpenGraph > Twitter Card > JSON-LD > 一般 HTML 標籤
+func ExtractMetadata(doc *goquery.Document, pageURL string) *model.ArticleMetadata {
+	base, _ := url.Parse(pageURL)
+	ld := findJSONLDArticle(doc)
+
+	meta := &model.ArticleMetadata{
+		Title: firstNonEmpty(
+			metaContent(doc, "property", "og:title"),
+			metaContent(doc, "name", "twitter:title"),
+			ld.headline(),
+			strings.TrimSpace(doc.Find("title").First().Text()),
+		),
+		Description: firstNonEmpty(
+			metaContent(doc, "property", "og:description"),
+			metaContent(doc, "name", "twitter:description"),
+			ld.string("description"),
+			metaContent(doc, "name", "description"),
+		),
+		ImageURL: resolveURL(base, firstNonEmpty(
+			metaContent(doc, "property", "og:image"),
+			metaContent(doc, "property", "og:image:url"),
+			metaContent(doc, "name", "twitter:image"),
+			metaContent(doc, "name", "twitter:image:src"),
+			ld.image(),
+		)),
+		Author: firstNonEmpty(
+			metaContent(doc, "name", "author"),
+			metaContent(doc, "property", "article:author"),
+			ld.author(),
+			metaContent(doc, "name", "twitter:creator"),
+		),
+		SiteName: firstNonEmpty(
+			metaContent(doc, "property", "og:site_name"),
+			ld.publisher(),
+			metaContent(doc, "name", "application-name"),
+			metaContent(doc, "name", "twitter:site"),
+		),
+		FaviconURL:    resolveURL(base, findFavicon(doc)),
+		CanonicalLink: resolveURL(base, firstNonEmpty(doc.Find("link[rel='canonical']").AttrOr("href", ""), metaContent(doc, "property", "og:url"))),
+		OEmbedURL: resolveURL(base, firstNonEmpty(
+			doc.Find("link[type='application/json+oembed']").AttrOr("href", ""),
+			doc.Find("link[type='text/xml+oembed']").AttrOr("href", ""),
+		)),
+		Language: firstNonEmpty(
+			strings.TrimSpace(doc.Find("html").AttrOr("lang", "")),
+			metaContent(doc, "http-equiv", "content-language"),
+			ld.string("inLanguage"),
+			strings.ReplaceAll(metaContent(doc, "property", "og:locale"), "_", "-"),
+		),
+		Keywords: firstNonEmptySlice(
+			splitKeywords(metaContent(doc, "name", "keywords")),
+			ld.keywords(),
+			metaContents(doc, "property", "article:tag"),
+		),
+		PublishedAt: parseDate(firstNonEmpty(
+			metaContent(doc, "property", "article:published_time"),
+			ld.string("datePublished"),
+			metaContent(doc, "name", "date"),
+			doc.Find("time[datetime]").First().AttrOr("datetime", ""),
+		)),
+		ModifiedAt: parseDate(firstNonEmpty(
+			metaContent(doc, "property", "article:modified_time"),
+			metaContent(doc, "property", "og:updated_time"),
+			ld.string("dateModified"),
+		)),
+	}
+
+	// 語言標籤 (BCP 47) 不會這麼長，過長代表是無效值
+	if len(meta.Language) > 35 {
+		meta.Language = ""
+	}
+
+	// 網站沒有宣告 favicon 時，瀏覽器會預設讀取根目錄的 /favicon.ico
+	if meta.FaviconURL == "" && base != nil && base.Host != "" {
+		meta.FaviconURL = resolveURL(base, "/favicon.ico")
+	}
+
+	return meta
+}
+
+func metaContent(doc *goquery.Document, attr, name string) string {
+	var content string
+	doc.Find("meta").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		if strings.EqualFold(s.AttrOr(attr, ""), name) {
+			content = strings.TrimSpace(s.AttrOr("content", ""))
+			return content == ""
+		}
+		return true
+	})
+	return content
+}
+
+func metaContents(doc *goquery.Document, attr, name string) []string {
+	var contents []string
+	doc.Find("meta").Each(func(_ int, s *goquery.Selection) {
+		if strings.EqualFold(s.AttrOr(attr, ""), name) {
+			if content := strings.TrimSpace(s.AttrOr("content", "")); content != "" {
+				contents = append(contents, content)
+			}
+		}
+	})
+	return contents
+}
+
+// findFavicon 依序尋找 icon、shortcut icon 與 apple-touch-icon
+func findFavicon(doc *goquery.Document) string {
+	var icon, fallback string
+	doc.Find("link[rel][href]").Each(func(_ int, s *goquery.Selection) {
+		for _, rel := range strings.Fields(strings.ToLower(s.AttrOr("rel", ""))) {
+			switch rel {
+			case "icon":
+				if icon == "" {
+					icon = s.AttrOr("href", "")
+				}
+			case "apple-touch-icon":
+				if fallback == "" {
+					fallback = s.AttrOr("href", "")
+				}
+			}
+		}
+	})
+	return firstNonEmpty(icon, fallback)
+}
+
+// resolveURL 以 base 解析相對網址，只保留 http(s) 網址，javascript:、data: 等其他 scheme 回傳空字串
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+
+	var u *url.URL
+	var err error
+	if base != nil {
+		u, err = base.Parse(ref)
+	} else {
+		u, err = url.Parse(ref)
+	}
+	if err != nil {
+		return ""
+	}
+	if scheme := strings.ToLower(u.Scheme); scheme != "http" && scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
+
+func parseDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+func splitKeywords(value string) []string {
+	var keywords []string
+	for _, k := range strings.Split(value, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keywords = append(keywords, k)
+		}
+	}
+	return keywords
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func firstNonEmptySlice(values ...[]string) []string {
+	for _, v := range values {
+		if len(v) > 0 {
+			return v
+		}
+	}
+	return nil
+}
+
+// jsonLDObject 是 JSON-LD 中的一個節點，欄位型別不固定，需逐一判斷
+type jsonLDObject map[string]any
+
+// findJSONLDArticle 從所有 application/ld+json 區塊中找出第一個文章類型的節點
+// 支援單一物件、陣列與 @graph 三種寫法
+func findJSONLDArticle(doc *goquery.Document) jsonLDObject {
+	var found jsonLDObject
+	doc.Find("script[type='application/ld+json']").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		var data any
+		if err := json.Unmarshal([]byte(s.Text()), &data); err != nil {
+			return true
+		}
+		found = searchJSONLD(data)
+		return found == nil
+	})
+	return found
+}
+
+func searchJSONLD(data any) jsonLDObject {
+	switch v := data.(type) {
+	case []any:
+		for _, item := range v {
+			if obj := searchJSONLD(item); obj != nil {
+				return obj
+			}
+		}
+	case map[string]any:
+		obj := jsonLDObject(v)
+		if obj.isArticle() {
+			return obj
+		}
+		if graph, ok := v["@graph"]; ok {
+			return searchJSONLD(graph)
+		}
+	}
+	return nil
+}
+
+func (o jsonLDObject) isArticle() bool {
+	switch t := o["@type"].(type) {
+	case string:
+		return jsonLDArticleTypes[t]
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok && jsonLDArticleTypes[s] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (o jsonLDObject) string(key string) string {
+	if o == nil {
+		return ""
+	}
+	s, _ := o[key].(string)
+	return s
+}
+
+func (o jsonLDObject) headline() string {
+	return firstNonEmpty(o.string("headline"), o.string("name"))
+}
+
+// image 可能是字串、ImageObject 或兩者的陣列
+func (o jsonLDObject) image() string {
+	if o == nil {
+		return ""
+	}
+	return jsonLDValue(o["image"], "url")
+}
+
+// author 可能是字串、Person/Organization 或兩者的陣列，多位作者以逗號串接
+func (o jsonLDObject) author() string {
+	if o == nil {
+		return ""
+	}
+
+	if authors, ok := o["author"].([]any); ok {
+		names := make([]string, 0, len(authors))
+		for _, a := range authors {
+			if name := jsonLDValue(a, "name"); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return jsonLDValue(o["author"], "name")
+}
+
+func (o jsonLDObject) publisher() string {
+	if o == nil {
+		return ""
+	}
+	return jsonLDValue(o["publisher"], "name")
+}
+
+// keywords 可能是逗號分隔的字串或字串陣列
+func (o jsonLDObject) keywords() []string {
+	if o == nil {
+		return nil
+	}
+
+	switch v := o["keywords"].(type) {
+	case string:
+		return splitKeywords(v)
+	case []any:
+		var keywords []string
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				keywords = append(keywords, strings.TrimSpace(s))
+			}
+		}
+		return keywords
+	}
+	return nil
+}
+
+// jsonLDValue 取出字串值；若是物件則取 key 欄位，若是陣列則取第一個有值的元素
+func jsonLDValue(v any, key string) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case map[string]any:
+		s, _ := t[key].(string)
+		return s
+	case []any:
+		for _, item := range t {
+			if s := jsonLDValue(item, key); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
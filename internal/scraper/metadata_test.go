@@ -0,0 +1,36 @@
+package scraper
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestResolveURL(t *testing.T) {
+	base, _ := url.Parse("https://example.com/posts/1")
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"/images/cover.png", "https://example.com/images/cover.png"},
+		{"cover.png", "https://example.com/posts/cover.png"},
+		{"//cdn.example.com/cover.png", "https://cdn.example.com/cover.png"},
+		{"http://cdn.example.com/cover.png", "http://cdn.example.com/cover.png"},
+		{"  ", ""},
+		// 只保留 http(s)，避免預覽圖等網址被用來轉址或嵌入其他 scheme
+		{"javascript:alert(1)", ""},
+		{"JavaScript:alert(1)", ""},
+		{"data:image/png;base64,iVBORw0KGgo=", ""},
+		{"ftp://example.com/cover.png", ""},
+		{"file:///etc/passwd", ""},
+	}
+	for _, tt := range tests {
+		if got := resolveURL(base, tt.ref); got != tt.want {
+			t.Errorf("resolveURL(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+
+	if got := resolveURL(nil, "javascript:alert(1)"); got != "" {
+		t.Errorf("resolveURL without base = %q, want empty", got)
+	}
+}
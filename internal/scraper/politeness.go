@@ -0,0 +1,279 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/temoto/robotstxt"
+	"golang.org/x/time/rate"
+)
+
+var (
+	ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+	ErrRobotsUnavailable  = errors.New("robots.txt temporarily unavailable")
+)
+
+// ThrottledError 表示目標主機目前已達頻率或並行上限，任務應延後而不是視為失敗
+type ThrottledError struct {
+	Host       string
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("host %s is throttled, retry after %s", e.Host, e.RetryAfter)
+}
+
+// PolitenessOptions 設定對單一主機的禮貌爬取規則，零值欄位使用預設值
+type PolitenessOptions struct {
+	RespectRobots  bool          // 是否遵守 robots.txt (含 Crawl-delay)
+	UserAgent      string        // 比對 robots.txt 群組用的 User-Agent
+	MaxConcurrency int           // 同一主機同時進行的請求數上限
+	MinDelay       time.Duration // 同一主機兩次請求的最短間隔，robots.txt 的 Crawl-delay 較長時以其為準
+	MaxWait        time.Duration // 等待配額的上限，超過就延後任務
+	RobotsCacheTTL time.Duration
+}
+
+// DefaultPolitenessOptions 是未設定時使用的預設值
+var DefaultPolitenessOptions = PolitenessOptions{
+	RespectRobots:  true,
+	UserAgent:      DefaultFetcherOptions.UserAgent,
+	MaxConcurrency: 2,
+	MinDelay:       time.Second,
+	MaxWait:        5 * time.Second,
+	RobotsCacheTTL: time.Hour,
+}
+
+// Politeness 在爬取前檢查 robots.txt，並限制每個主機的並行數與請求頻率
+// 狀態保存在程序內，多個 worker 程序之間不共享
+type Politeness struct {
+	fetcher *Fetcher
+	opts    PolitenessOptions
+	agent   string // robots.txt 比對用的產品名稱，例如 DeeliaiBot
+
+	mu     sync.Mutex
+	hosts  map[string]*hostState
+	robots map[string]*robotsEntry
+}
+
+type hostState struct {
+	limiter  *rate.Limiter
+	inFlight int
+	lastUsed time.Time
+}
+
+type robotsEntry struct {
+	data      *robotstxt.RobotsData
+	expiresAt time.Time
+	ready     chan struct{} // 關閉後代表 data 已載入，避免同一主機同時抓取多次 robots.txt
+	err       error
+}
+
+func NewPoliteness(fetcher *Fetcher, opts PolitenessOptions) *Politeness {
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultPolitenessOptions.UserAgent
+	}
+	if opts.MaxConcurrency <= 0 {
+		opts.MaxConcurrency = DefaultPolitenessOptions.MaxConcurrency
+	}
+	if opts.MinDelay <= 0 {
+		opts.MinDelay = DefaultPolitenessOptions.MinDelay
+	}
+	if opts.MaxWait < 0 {
+		opts.MaxWait = 0
+	}
+	if opts.RobotsCacheTTL <= 0 {
+		opts.RobotsCacheTTL = DefaultPolitenessOptions.RobotsCacheTTL
+	}
+
+	agent, _, _ := strings.Cut(opts.UserAgent, "/")
+
+	return &Politeness{
+		fetcher: fetcher,
+		opts:    opts,
+		agent:   strings.TrimSpace(agent),
+		hosts:   make(map[string]*hostState),
+		robots:  make(map[string]*robotsEntry),
+	}
+}
+
+// Acquire 取得爬取 rawURL 的許可，成功時回傳的 release 必須在請求結束後呼叫
+// 主機忙碌時回傳 *ThrottledError；robots.txt 不允許時回傳 ErrDisallowedByRobots
+func (p *Politeness) Acquire(ctx context.Context, rawURL string) (release func(), err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	host := strings.ToLower(u.Host)
+
+	crawlDelay := time.Duration(0)
+	if p.opts.RespectRobots {
+		robots, err := p.loadRobots(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		if !robots.TestAgent(robotsPath(u), p.agent) {
+			return nil, fmt.Errorf("%w: %s", ErrDisallowedByRobots, rawURL)
+		}
+		crawlDelay = robots.FindGroup(p.agent).CrawlDelay
+	}
+
+	interval := max(p.opts.MinDelay, crawlDelay)
+
+	p.mu.Lock()
+	state := p.hostState(host, interval)
+	if state.inFlight >= p.opts.MaxConcurrency {
+		p.mu.Unlock()
+		return nil, &ThrottledError{Host: host, RetryAfter: max(interval, p.opts.MaxWait)}
+	}
+
+	reservation := state.limiter.Reserve()
+	delay := reservation.Delay()
+	if delay > p.opts.MaxWait {
+		reservation.Cancel()
+		p.mu.Unlock()
+		return nil, &ThrottledError{Host: host, RetryAfter: delay}
+	}
+	state.inFlight++
+	p.mu.Unlock()
+
+	release = func() {
+		p.mu.Lock()
+		state.inFlight--
+		state.lastUsed = time.Now()
+		p.mu.Unlock()
+	}
+
+	// 配額很快就會補上，直接等待比延後任務划算
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return release, nil
+}
+
+// hostState 取得主機的狀態，Crawl-delay 改變時同步更新 limiter；呼叫端需持有 p.mu
+func (p *Politeness) hostState(host string, interval time.Duration) *hostState {
+	p.cleanup()
+
+	state, ok := p.hosts[host]
+	if !ok {
+		state = &hostState{limiter: rate.NewLimiter(rate.Every(interval), 1)}
+		p.hosts[host] = state
+	} else if state.limiter.Limit() != rate.Every(interval) {
+		state.limiter.SetLimit(rate.Every(interval))
+	}
+	state.lastUsed = time.Now()
+
+	return state
+}
+
+// cleanup 移除閒置的主機狀態與過期的 robots.txt，避免 map 無限成長；呼叫端需持有 p.mu
+func (p *Politeness) cleanup() {
+	if len(p.hosts) < 1000 {
+		return
+	}
+
+	for host, state := range p.hosts {
+		if state.inFlight == 0 && time.Since(state.lastUsed) > 10*time.Minute {
+			delete(p.hosts, host)
+		}
+	}
+	for key, entry := range p.robots {
+		select {
+		case <-entry.ready:
+			if time.Now().After(entry.expiresAt) {
+				delete(p.robots, key)
+			}
+		default:
+		}
+	}
+}
+
+// loadRobots 取得主機的 robots.txt，結果會快取 RobotsCacheTTL
+// 4xx 視為沒有限制；5xx 或連線失敗回傳包裝了原始錯誤的 ErrRobotsUnavailable，交由重試策略分類
+func (p *Politeness) loadRobots(ctx context.Context, u *url.URL) (*robotstxt.RobotsData, error) {
+	key := u.Scheme + "://" + strings.ToLower(u.Host)
+
+	p.mu.Lock()
+	entry, ok := p.robots[key]
+	if ok && time.Now().After(entry.expiresAt) {
+		select {
+		case <-entry.ready:
+			ok = false
+		default:
+		}
+	}
+	if !ok {
+		entry = &robotsEntry{ready: make(chan struct{})}
+		p.robots[key] = entry
+		p.mu.Unlock()
+
+		// 結果由所有等待同一主機的呼叫者共用，不能因為第一個呼叫者的 ctx 被取消而快取失敗結果
+		// Fetcher 本身有連線與讀取逾時，不會無限等待
+		entry.data, entry.err = p.fetchRobots(context.WithoutCancel(ctx), key+"/robots.txt")
+		ttl := p.opts.RobotsCacheTTL
+		if entry.err != nil {
+			// 暫時性錯誤只快取一小段時間
+			ttl = time.Minute
+		}
+		entry.expiresAt = time.Now().Add(ttl)
+		close(entry.ready)
+	} else {
+		p.mu.Unlock()
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-entry.ready:
+	}
+
+	return entry.data, entry.err
+}
+
+func (p *Politeness) fetchRobots(ctx context.Context, robotsURL string) (*robotstxt.RobotsData, error) {
+	result, err := p.fetcher.Fetch(ctx, robotsURL, "text/plain")
+	if err != nil {
+		// 內容不是純文字 (例如導向 HTML 首頁) 視為沒有 robots.txt
+		if errors.Is(err, ErrUnsupportedContentType) {
+			return robotstxt.FromStatusAndBytes(http.StatusNotFound, nil)
+		}
+		// 保留原始錯誤，讓被擋下的位址或不存在的網域等仍被 ClassifyScrapeError 判定為永久性錯誤
+		return nil, fmt.Errorf("%w: %w", ErrRobotsUnavailable, err)
+	}
+	if result.StatusCode >= 500 {
+		return nil, fmt.Errorf("%w: status %d", ErrRobotsUnavailable, result.StatusCode)
+	}
+
+	robots, err := robotstxt.FromStatusAndBytes(result.StatusCode, result.Body)
+	if err != nil {
+		// 解析失敗或非預期的狀態碼 (例如 3xx 轉址過多) 視為沒有限制
+		return robotstxt.FromStatusAndBytes(http.StatusNotFound, nil)
+	}
+
+	return robots, nil
+}
+
+func robotsPath(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path
+}
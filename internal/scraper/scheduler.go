@@ -5,24 +5,32 @@ import (
 	"deeliai/internal/interfaces"
 	"log"
 	"time"
+
+	"github.com/google/uuid"
 )
 
-// ScrapeScheduler 定時檢查失敗的爬取任務並重新排入佇列
+// ScrapeScheduler 定時檢查到達重試時間的失敗或延後任務並重新排入佇列，也會重新爬取內容過期的頁面
 type ScrapeScheduler struct {
-	articleRepo interfaces.ArticleRepository
-	producer    interfaces.QueueProducer
+	pageRepo     interfaces.PageRepository
+	producer     interfaces.QueueProducer
+	interval     time.Duration
+	refreshTTL   time.Duration // 0 代表不重新爬取
+	refreshBatch int           // 每次檢查最多重新排入的到期頁面數與過期頁面數，避免同時送出大量請求
 }
 
-func NewScrapeScheduler(repo interfaces.ArticleRepository, producer interfaces.QueueProducer) *ScrapeScheduler {
+func NewScrapeScheduler(repo interfaces.PageRepository, producer interfaces.QueueProducer, interval, refreshTTL time.Duration, refreshBatch int) *ScrapeScheduler {
 	return &ScrapeScheduler{
-		articleRepo: repo,
-		producer:    producer,
+		pageRepo:     repo,
+		producer:     producer,
+		interval:     interval,
+		refreshTTL:   refreshTTL,
+		refreshBatch: refreshBatch,
 	}
 }
 
-// Start 啟動排程器，每隔 5 分鐘檢查一次
+// Start 啟動排程器，每隔 interval 檢查一次；實際的重試時間由各頁面的 next_attempt_at 決定
 func (s *ScrapeScheduler) Start(ctx context.Context) {
-	ticker := time.NewTicker(5 * time.Minute)
+	ticker := time.NewTicker(s.interval)
 	defer ticker.Stop()
 
 	log.Println("Scrape Scheduler started...")
@@ -42,17 +50,44 @@ func (s *ScrapeScheduler) Start(ctx context.Context) {
 }
 
 func (s *ScrapeScheduler) checkAndRequeue(ctx context.Context) {
-	log.Println("Checking for failed scrape tasks...")
-	articles, err := s.articleRepo.FindFailedScrapes(ctx)
+	log.Println("Checking for due scrape tasks...")
+	ids, err := s.pageRepo.ClaimDueScrapes(ctx, s.refreshBatch)
 	if err != nil {
-		log.Printf("Error finding failed scrapes: %v", err)
+		log.Printf("Error claiming due scrapes: %v", err)
 		return
 	}
 
-	for _, article := range articles {
-		log.Printf("Re-queuing failed scrape task for article ID: %s", article.ID.String())
-		if err := s.producer.Produce(article.ID.String()); err != nil {
-			log.Printf("Failed to requeue article %s: %v", article.ID.String(), err)
+	for _, id := range ids {
+		log.Printf("Re-queuing scrape task for page ID: %s", id.String())
+		s.produce(ctx, id)
+	}
+
+	if s.refreshTTL > 0 {
+		s.refreshStale(ctx)
+	}
+}
+
+// refreshStale 將爬取成功超過 refreshTTL、且仍有人收藏的頁面重新排入佇列
+func (s *ScrapeScheduler) refreshStale(ctx context.Context) {
+	ids, err := s.pageRepo.ResetStaleScrapes(ctx, time.Now().Add(-s.refreshTTL), s.refreshBatch)
+	if err != nil {
+		log.Printf("Error finding stale pages: %v", err)
+		return
+	}
+
+	for _, id := range ids {
+		log.Printf("Re-queuing stale page ID: %s", id.String())
+		s.produce(ctx, id)
+	}
+}
+
+// produce 將已認領 (改為待爬取) 的頁面排入佇列
+// 已認領的頁面不會再被檢查到，排入失敗時改為延後，讓下一次檢查重新認領
+func (s *ScrapeScheduler) produce(ctx context.Context, pageID uuid.UUID) {
+	if err := s.producer.Produce(pageID.String()); err != nil {
+		log.Printf("Failed to requeue page %s: %v", pageID.String(), err)
+		if err := s.pageRepo.MarkScrapeDeferred(ctx, pageID, time.Now().Add(s.interval)); err != nil {
+			log.Printf("Failed to defer page %s: %v", pageID.String(), err)
 		}
 	}
 }
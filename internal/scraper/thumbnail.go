@@ -0,0 +1,54 @@
+package scraper
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+
+	"github.com/disintegration/imaging"
+	_ "golang.org/x/image/webp"
+)
+
+// maxSourcePixels 是可接受的原圖像素上限，避免解壓縮炸彈耗盡記憶體
+const maxSourcePixels = 40_000_000
+
+// ImageAcceptTypes 是下載預覽圖時允許的 Content-Type
+var ImageAcceptTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+var ErrImageTooLarge = errors.New("image dimensions exceed limit")
+
+// MakeThumbnail 將圖片等比例縮小至 maxWidth x maxHeight 以內，輸出 JPEG
+// 原圖比縮圖小時不會放大
+func MakeThumbnail(data []byte, maxWidth, maxHeight int) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width*cfg.Height > maxSourcePixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrImageTooLarge, cfg.Width, cfg.Height)
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, err
+	}
+
+	if img.Bounds().Dx() > maxWidth || img.Bounds().Dy() > maxHeight {
+		img = imaging.Fit(img, maxWidth, maxHeight, imaging.Lanczos)
+	}
+
+	// JPEG 不支援透明，先鋪上白底，否則透明處會變成黑色
+	canvas := imaging.New(img.Bounds().Dx(), img.Bounds().Dy(), color.White)
+	canvas = imaging.Overlay(canvas, img, image.Pt(0, 0), 1)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, canvas, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
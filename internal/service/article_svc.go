@@ -3,55 +3,346 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"time"
 
 	"deeliai/internal/interfaces"
 	"deeliai/internal/model"
+	"deeliai/internal/scraper"
+	"deeliai/internal/urlnorm"
 
 	"github.com/google/uuid"
 )
 
+// maxBulkRescrape 是單次批次重新爬取的文章數上限
+const maxBulkRescrape = 500
+
 type ArticleService struct {
-	articleRepo interfaces.ArticleRepository
-	producer    interfaces.QueueProducer // 依賴介面
+	articleRepo    interfaces.ArticleRepository
+	pageRepo       interfaces.PageRepository
+	attemptRepo    interfaces.ScrapeAttemptRepository
+	contentRepo    interfaces.PageContentRepository
+	collectionRepo interfaces.CollectionRepository
+	imageStore     interfaces.BlobStore     // 未啟用預覽圖快取時為 nil
+	producer       interfaces.QueueProducer // 依賴介面
+	urlGuard       *scraper.URLGuard
+	refreshTTL     time.Duration // 頁面爬取成功超過此時間，再次被收藏時重新爬取；0 代表不重新爬取
 }
 
-func NewArticleService(repo interfaces.ArticleRepository, producer interfaces.QueueProducer) *ArticleService {
+func NewArticleService(repo interfaces.ArticleRepository, pageRepo interfaces.PageRepository, attemptRepo interfaces.ScrapeAttemptRepository, contentRepo interfaces.PageContentRepository, collectionRepo interfaces.CollectionRepository, imageStore interfaces.BlobStore, producer interfaces.QueueProducer, urlGuard *scraper.URLGuard, refreshTTL time.Duration) *ArticleService {
 	return &ArticleService{
-		articleRepo: repo,
-		producer:    producer,
+		articleRepo:    repo,
+		pageRepo:       pageRepo,
+		attemptRepo:    attemptRepo,
+		contentRepo:    contentRepo,
+		collectionRepo: collectionRepo,
+		imageStore:     imageStore,
+		producer:       producer,
+		urlGuard:       urlGuard,
+		refreshTTL:     refreshTTL,
 	}
 }
 
 // CreateArticle 處理文章儲存和爬取任務分派
-func (s *ArticleService) CreateArticle(ctx context.Context, url, userEmail string) (*model.Article, error) {
-	article := &model.Article{
+// URL 指向內網或保留位址時回傳 scraper.ErrBlockedAddress / scraper.ErrUnsupportedScheme
+// 使用者已收藏過正規化後相同的網址 (或爬取到的 canonical 網址相同) 時不會新增，回傳既有文章且 duplicate 為 true
+// 其他使用者已收藏過的網址會共用同一個頁面，不會再爬取一次
+func (s *ArticleService) CreateArticle(ctx context.Context, url, userEmail string) (article *model.Article, duplicate bool, err error) {
+	// 0. 提早拒絕明顯不合法的目標，爬取時 Fetcher 仍會在連線前再檢查一次
+	if err := s.urlGuard.CheckURL(ctx, url); err != nil {
+		return nil, false, err
+	}
+
+	canonicalURL, err := urlnorm.Normalize(url)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// 1. 已收藏過就直接回傳既有文章
+	existing, err := s.articleRepo.FindByCanonicalURL(ctx, userEmail, canonicalURL)
+	if err == nil {
+		return existing, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, err
+	}
+
+	// 2. 取得或建立共用的頁面；正規化後超過欄位長度的網址以原始網址為 key，也不做重複偵測
+	pageKey := canonicalURL
+	if len(canonicalURL) > maxURLLength {
+		pageKey = url
+	}
+	page, pageCreated, err := s.pageRepo.FindOrCreate(ctx, pageKey, url)
+	if err != nil {
+		return nil, false, err
+	}
+
+	newArticle := &model.Article{
 		UserEmail: userEmail,
 		URL:       url,
+		PageID:    page.ID,
+	}
+	if pageKey == canonicalURL {
+		newArticle.CanonicalURL = &canonicalURL
+	}
+
+	// 3. 儲存文章到資料庫
+	createdArticle, err := s.articleRepo.Create(ctx, newArticle)
+	if errors.Is(err, interfaces.ErrArticleExists) {
+		// 同時送出的相同網址，由唯一索引擋下
+		existing, err := s.articleRepo.FindByCanonicalURL(ctx, userEmail, canonicalURL)
+		if err != nil {
+			return nil, false, err
+		}
+		return existing, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	s.addToInbox(ctx, userEmail, createdArticle.ID)
+
+	// 4. 新頁面或內容已過期的頁面才推入爬取佇列，讓 worker 處理；爬取中或等待重試的頁面交給既有的任務
+	if pageCreated {
+		s.enqueueScrape(ctx, page.ID)
+	} else if s.refreshTTL > 0 {
+		stale, err := s.pageRepo.ResetIfStale(ctx, page.ID, time.Now().Add(-s.refreshTTL))
+		if err != nil {
+			log.Printf("Failed to check whether page %s is stale: %v", page.ID.String(), err)
+		}
+		if stale {
+			s.enqueueScrape(ctx, page.ID)
+			createdArticle.ScrapeStatus = model.ScrapeStatusPending
+		}
+	}
+
+	return createdArticle, false, nil
+}
+
+// addToInbox 將新收藏的文章放進使用者的 Inbox，失敗時只記錄，不影響收藏本身
+func (s *ArticleService) addToInbox(ctx context.Context, userEmail string, articleID uuid.UUID) {
+	inbox, err := s.collectionRepo.FindOrCreateDefault(ctx, userEmail)
+	if err == nil {
+		err = s.collectionRepo.AddArticle(ctx, inbox.ID, articleID)
+	}
+	if err != nil {
+		log.Printf("Failed to add article %s to inbox: %v", articleID.String(), err)
+	}
+}
+
+// RescrapeArticle 重設文章所屬頁面的爬取狀態並重新排入佇列，不受重試次數上限限制
+// 頁面由所有收藏同一網址的使用者共用，重新爬取的結果也會更新到他們的文章
+func (s *ArticleService) RescrapeArticle(ctx context.Context, articleUUID uuid.UUID, userEmail string) (*model.Article, error) {
+	article, err := s.articleRepo.FindByIDAndUserEmail(ctx, articleUUID, userEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.pageRepo.ResetScrape(ctx, article.PageID); err != nil {
+		return nil, err
+	}
+
+	s.enqueueScrape(ctx, article.PageID)
+
+	return s.articleRepo.FindByIDAndUserEmail(ctx, articleUUID, userEmail)
+}
+
+// BulkRescrapeArticles 依條件批次重新爬取使用者文章所屬的頁面，回傳排入佇列的頁面數
+func (s *ArticleService) BulkRescrapeArticles(ctx context.Context, userEmail string, filter model.RescrapeFilter) (int, error) {
+	ids, err := s.pageRepo.ResetScrapesByFilter(ctx, userEmail, filter, maxBulkRescrape)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		s.enqueueScrape(ctx, id)
+	}
+
+	return len(ids), nil
+}
+
+// enqueueScrape 將頁面 ID 推入爬取佇列
+// 這裡直接呼叫 producer 的 Produce 方法，不關心底層是誰
+// 文章已成功儲存，排入佇列失敗時改標記為爬取失敗交給排程器重試，而不是讓整個請求失敗
+func (s *ArticleService) enqueueScrape(ctx context.Context, pageID uuid.UUID) {
+	if err := s.producer.Produce(pageID.String()); err != nil {
+		log.Printf("Failed to produce page %s to queue, leaving it to the scheduler: %v", pageID.String(), err)
+		if err := s.pageRepo.MarkScrapeFailed(ctx, pageID, time.Now(), err.Error()); err != nil {
+			log.Printf("Failed to mark scrape as failed: %v", err)
+		}
+	}
+}
+
+// ErrInvalidCursor 表示分頁 cursor 無法解析，或與這次查詢的排序方式不同
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ArticleList 是一頁文章列表，NextCursor 為空代表沒有下一頁
+type ArticleList struct {
+	Articles   []model.Article
+	NextCursor string
+	Total      int // 符合篩選條件的文章總數
+}
+
+// articleCursor 是 cursor 編碼前的內容，記錄排序方式以拒絕與查詢不符的 cursor
+type articleCursor struct {
+	Sort    string    `json:"s"`
+	Desc    bool      `json:"d"`
+	SortKey string    `json:"k"`
+	ID      uuid.UUID `json:"i"`
+}
+
+// GetArticles 以 keyset 分頁取得使用者儲存的文章列表，cursor 為上一頁回傳的 NextCursor，第一頁傳空字串
+func (s *ArticleService) GetArticles(ctx context.Context, userEmail string, query model.ArticleQuery, cursor string) (*ArticleList, error) {
+	return listArticles(ctx, s.articleRepo, userEmail, query, cursor)
+}
+
+// listArticles 是文章列表與收藏夾文章列表共用的 keyset 分頁
+func listArticles(ctx context.Context, articleRepo interfaces.ArticleRepository, userEmail string, query model.ArticleQuery, cursor string) (*ArticleList, error) {
+	query.Filter.Tag = normalizeTag(query.Filter.Tag)
+	if cursor != "" {
+		after, err := decodeArticleCursor(cursor, query.Sort, query.Desc)
+		if err != nil {
+			return nil, err
+		}
+		query.After = after
 	}
 
-	// 1. 儲存文章到資料庫，狀態為 pending
-	createdArticle, err := s.articleRepo.Create(ctx, article)
+	articles, next, err := articleRepo.ListByUserEmail(ctx, userEmail, query)
 	if err != nil {
 		return nil, err
 	}
 
-	// 2. 將文章 ID 推入爬取佇列，讓 worker 處理
-	// 這裡直接呼叫 producer 的 Produce 方法，不關心底層是誰
-	if err := s.producer.Produce(createdArticle.ID.String()); err != nil {
-		return nil, fmt.Errorf("failed to produce message to queue: %w", err)
+	total, err := articleRepo.CountByUserEmail(ctx, userEmail, query.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &ArticleList{Articles: articles, Total: total}
+	if next != nil {
+		list.NextCursor = encodeArticleCursor(query.Sort, query.Desc, next)
+	}
+
+	return list, nil
+}
+
+// encodeArticleCursor 將分頁位置編碼為不透明的字串，用戶端只需原封不動帶回
+func encodeArticleCursor(sort string, desc bool, c *model.ArticleCursor) string {
+	data, _ := json.Marshal(articleCursor{Sort: sort, Desc: desc, SortKey: c.SortKey, ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeArticleCursor(cursor, sort string, desc bool) (*model.ArticleCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c articleCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort != sort || c.Desc != desc {
+		return nil, ErrInvalidCursor
+	}
+
+	return &model.ArticleCursor{SortKey: c.SortKey, ID: c.ID}, nil
+}
+
+// SearchArticles 以全文搜尋使用者的文章，依相關度排序
+func (s *ArticleService) SearchArticles(ctx context.Context, userEmail, query string, limit, offset int) ([]model.ArticleSearchResult, error) {
+	return s.articleRepo.Search(ctx, userEmail, strings.TrimSpace(query), limit, offset)
+}
+
+// UpdateArticle 修改使用者自訂的標題、描述與筆記，文章不存在或不屬於使用者時回傳 sql.ErrNoRows
+func (s *ArticleService) UpdateArticle(ctx context.Context, articleUUID uuid.UUID, userEmail string, update model.ArticleUpdate) (*model.Article, error) {
+	// 先確認文章屬於該使用者
+	article, err := s.articleRepo.FindByIDAndUserEmail(ctx, articleUUID, userEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range []*string{update.CustomTitle, update.CustomDescription, update.Notes} {
+		if v != nil {
+			*v = strings.TrimSpace(*v)
+		}
+	}
+
+	return s.articleRepo.Update(ctx, article.ID, userEmail, update)
+}
+
+// ErrNoStateChange 表示沒有指定要修改的閱讀狀態
+var ErrNoStateChange = errors.New("no state to update")
+
+// UpdateArticleState 修改單篇文章的已讀、封存與最愛狀態，回傳修改後的文章
+func (s *ArticleService) UpdateArticleState(ctx context.Context, articleUUID uuid.UUID, userEmail string, update model.ArticleStateUpdate) (*model.Article, error) {
+	if update == (model.ArticleStateUpdate{}) {
+		return nil, ErrNoStateChange
 	}
 
-	return createdArticle, nil
+	updated, err := s.articleRepo.UpdateState(ctx, userEmail, []uuid.UUID{articleUUID}, update)
+	if err != nil {
+		return nil, err
+	}
+	if updated == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return s.articleRepo.FindByIDAndUserEmail(ctx, articleUUID, userEmail)
 }
 
-// GetArticles 取得使用者儲存的文章列表
-func (s *ArticleService) GetArticles(ctx context.Context, userEmail string, page, limit int) ([]model.Article, error) {
-	offset := (page - 1) * limit
-	return s.articleRepo.ListByUserEmail(ctx, userEmail, limit, offset)
+// BulkUpdateArticleState 批次修改文章的閱讀狀態，不屬於使用者的文章會被略過，回傳實際修改的文章數
+func (s *ArticleService) BulkUpdateArticleState(ctx context.Context, userEmail string, articleUUIDs []uuid.UUID, update model.ArticleStateUpdate) (int, error) {
+	if update == (model.ArticleStateUpdate{}) {
+		return 0, ErrNoStateChange
+	}
+
+	return s.articleRepo.UpdateState(ctx, userEmail, articleUUIDs, update)
 }
 
-// DeleteArticle 刪除使用者收藏的文章
+// DeleteArticle 刪除使用者收藏的文章；頁面與縮圖是共用的快取，會保留給其他使用者與之後的收藏
 func (s *ArticleService) DeleteArticle(ctx context.Context, articleUUID uuid.UUID, userEmail string) error {
 	return s.articleRepo.Delete(ctx, articleUUID, userEmail)
 }
+
+// ListScrapeAttempts 取得使用者文章所屬頁面最近的爬取紀錄
+func (s *ArticleService) ListScrapeAttempts(ctx context.Context, articleUUID uuid.UUID, userEmail string) ([]model.ScrapeAttempt, error) {
+	// 先確認文章屬於該使用者
+	article, err := s.articleRepo.FindByIDAndUserEmail(ctx, articleUUID, userEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.attemptRepo.ListByPageID(ctx, article.PageID, 50)
+}
+
+// GetArticleContent 取得使用者文章擷取出的內文，文章不存在或尚未擷取時回傳 sql.ErrNoRows
+func (s *ArticleService) GetArticleContent(ctx context.Context, articleUUID uuid.UUID, userEmail string) (*model.PageContent, error) {
+	// 先確認文章屬於該使用者
+	article, err := s.articleRepo.FindByIDAndUserEmail(ctx, articleUUID, userEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.contentRepo.FindByPageID(ctx, article.PageID)
+}
+
+// GetArticleImage 取得文章快取的預覽圖縮圖 (需由呼叫端關閉)，文章不存在或尚未快取縮圖時回傳 sql.ErrNoRows
+// 不回傳原始的 image_url 供轉址，避免 API 成為轉址到頁面任意宣告網址的跳板
+func (s *ArticleService) GetArticleImage(ctx context.Context, articleUUID uuid.UUID, userEmail string) (io.ReadCloser, error) {
+	article, err := s.articleRepo.FindByIDAndUserEmail(ctx, articleUUID, userEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.imageStore == nil || article.ThumbnailKey == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	thumbnail, err := s.imageStore.Get(ctx, *article.ThumbnailKey)
+	if errors.Is(err, interfaces.ErrBlobNotFound) {
+		return nil, sql.ErrNoRows
+	}
+	return thumbnail, err
+}
@@ -1,44 +1,195 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"slices"
+	"strings"
 	"time"
 
+	"deeliai/internal/interfaces"
+	"deeliai/internal/model"
+
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// maxUserAgentLength 與 sessions.user_agent 欄位長度一致，過長的 User-Agent 會被截斷而不是讓登入失敗
+const maxUserAgentLength = 512
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrSessionRevoked      = errors.New("session revoked or expired")
 )
 
 // Claims 定義 JWT 中包含的資料
 type Claims struct {
-	Email string `json:"email"`
+	Email     string `json:"email"`
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
 type AuthService struct {
-	JWTSecret string
+	keys            *KeySet
+	issuer          string
+	sessionRepo     interfaces.SessionRepository
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
 }
 
-func NewAuthService(JWTSecret string) *AuthService {
-	return &AuthService{JWTSecret: JWTSecret}
+func NewAuthService(keys *KeySet, issuer string, sessionRepo interfaces.SessionRepository, accessTokenTTL, refreshTokenTTL time.Duration) *AuthService {
+	// 設定檔未指定時使用預設值
+	if accessTokenTTL <= 0 {
+		accessTokenTTL = 15 * time.Minute
+	}
+	if refreshTokenTTL <= 0 {
+		refreshTokenTTL = 30 * 24 * time.Hour
+	}
+
+	return &AuthService{
+		keys:            keys,
+		issuer:          issuer,
+		sessionRepo:     sessionRepo,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+	}
+}
+
+// Login 為使用者建立新的 Session，並簽發 access token 與 refresh token
+func (s *AuthService) Login(ctx context.Context, email, userAgent, ip string) (*model.TokenPair, error) {
+	secret, hash, err := newRefreshSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.Create(ctx, &model.Session{
+		UserEmail:        email,
+		RefreshTokenHash: hash,
+		UserAgent:        truncateRunes(userAgent, maxUserAgentLength),
+		IPAddress:        ip,
+		ExpiresAt:        time.Now().Add(s.refreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokenPair(session, secret)
 }
 
-// GenerateToken 根據使用者 ID 產生 JWT
-func (s *AuthService) GenerateToken(email string) (string, error) {
+// Refresh 以 refresh token 換發新的 token 組合，並輪替 refresh token
+// 若收到已被輪替過的舊 token，視為 token 外洩，直接撤銷整個 Session；從未簽發過的 token 只回傳 ErrInvalidRefreshToken
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*model.TokenPair, error) {
+	sessionID, secret, err := splitRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	newSecret, newHash, err := newRefreshSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hashRefreshSecret(secret)
+	session, err := s.sessionRepo.RotateRefreshToken(ctx, sessionID, hash, newHash)
+	if err == nil {
+		return s.issueTokenPair(session, newSecret)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	// 輪替失敗，判斷是 Session 失效還是舊 token 被重複使用
+	session, err = s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if !isSessionActive(session) {
+		return nil, ErrSessionRevoked
+	}
+
+	// sid 是公開的 (access token 的 sid claim 與 refresh token 的前綴)，只有曾經有效的舊 token 被重送才撤銷 Session
+	// 其他不相符的 token 只回傳錯誤，避免任何拿到 sid 的人都能把使用者登出
+	if !slices.Contains(session.PreviousRefreshTokenHashes, hash) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	log.Printf("Refresh token reuse detected, revoking session %s of %s", session.ID.String(), session.UserEmail)
+	if err := s.sessionRepo.Revoke(ctx, session.ID); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrRefreshTokenReused
+}
+
+// Logout 撤銷單一 Session
+func (s *AuthService) Logout(ctx context.Context, sessionID string) error {
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return ErrSessionRevoked
+	}
+
+	return s.sessionRepo.Revoke(ctx, id)
+}
+
+// LogoutAll 撤銷使用者所有 Session (所有裝置登出)
+func (s *AuthService) LogoutAll(ctx context.Context, email string) error {
+	return s.sessionRepo.RevokeAllByUserEmail(ctx, email)
+}
+
+// ValidateSession 確認 access token 所屬的 Session 尚未被撤銷或過期
+func (s *AuthService) ValidateSession(ctx context.Context, claims *Claims) error {
+	id, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return ErrSessionRevoked
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, id)
+	if err != nil {
+		return ErrSessionRevoked
+	}
+
+	if session.UserEmail != claims.Email || !isSessionActive(session) {
+		return ErrSessionRevoked
+	}
+
+	return nil
+}
+
+// GenerateToken 根據使用者 ID 與 Session ID 產生 JWT
+func (s *AuthService) GenerateToken(email, sessionID string) (string, error) {
 	claims := &Claims{
-		Email: email,
+		Email:     email,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)), // Token 有效期限 24 小時
+			Issuer:    s.issuer,
+			Subject:   email,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.accessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.JWTSecret))
+	// 以目前的 active 金鑰簽發，並在 header 帶上 kid 讓驗證端挑選對應公鑰
+	key := s.keys.Active()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.KID
+	return token.SignedString(key.PrivateKey)
 }
 
 // ParseToken 解析並驗證 JWT，成功則回傳 Claims
 func (s *AuthService) ParseToken(tokenStr string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.JWTSecret), nil
-	})
+	var opts []jwt.ParserOption
+	if s.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(s.issuer))
+	}
+
+	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, s.keys.Keyfunc, opts...)
 
 	if err != nil {
 		return nil, err
@@ -50,3 +201,56 @@ func (s *AuthService) ParseToken(tokenStr string) (*Claims, error) {
 
 	return nil, jwt.ErrInvalidKey
 }
+
+// JWKS 回傳可公開的驗證公鑰，供其他服務驗證 Deeliai 簽發的 token
+func (s *AuthService) JWKS() JWKSet {
+	return s.keys.JWKS()
+}
+
+func (s *AuthService) issueTokenPair(session *model.Session, secret string) (*model.TokenPair, error) {
+	accessToken, err := s.GenerateToken(session.UserEmail, session.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: session.ID.String() + "." + secret,
+		ExpiresIn:    int(s.accessTokenTTL.Seconds()),
+	}, nil
+}
+
+func isSessionActive(session *model.Session) bool {
+	return session.RevokedAt == nil && time.Now().Before(session.ExpiresAt)
+}
+
+// newRefreshSecret 產生隨機的 refresh token 密鑰，資料庫只保存其雜湊
+func newRefreshSecret() (secret, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	secret = base64.RawURLEncoding.EncodeToString(b)
+	return secret, hashRefreshSecret(secret), nil
+}
+
+func hashRefreshSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// splitRefreshToken 拆解 "<session id>.<secret>" 格式的 refresh token
+func splitRefreshToken(token string) (uuid.UUID, string, error) {
+	sessionPart, secret, ok := strings.Cut(token, ".")
+	if !ok || secret == "" {
+		return uuid.Nil, "", ErrInvalidRefreshToken
+	}
+
+	sessionID, err := uuid.Parse(sessionPart)
+	if err != nil {
+		return uuid.Nil, "", ErrInvalidRefreshToken
+	}
+
+	return sessionID, secret, nil
+}
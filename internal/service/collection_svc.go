@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"deeliai/internal/interfaces"
+	"deeliai/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCollectionName 表示收藏夾名稱去除前後空白後為空
+var ErrInvalidCollectionName = errors.New("collection name must not be empty")
+
+// ErrDefaultCollection 表示不能刪除預設的 Inbox
+var ErrDefaultCollection = errors.New("default collection cannot be deleted")
+
+type CollectionService struct {
+	collectionRepo interfaces.CollectionRepository
+	articleRepo    interfaces.ArticleRepository
+}
+
+func NewCollectionService(collectionRepo interfaces.CollectionRepository, articleRepo interfaces.ArticleRepository) *CollectionService {
+	return &CollectionService{
+		collectionRepo: collectionRepo,
+		articleRepo:    articleRepo,
+	}
+}
+
+// ListCollections 取得使用者所有收藏夾，第一次使用時建立 Inbox
+func (s *CollectionService) ListCollections(ctx context.Context, userEmail string) ([]model.Collection, error) {
+	if _, err := s.collectionRepo.FindOrCreateDefault(ctx, userEmail); err != nil {
+		return nil, err
+	}
+
+	return s.collectionRepo.ListByUserEmail(ctx, userEmail)
+}
+
+// GetCollection 取得使用者的單一收藏夾
+func (s *CollectionService) GetCollection(ctx context.Context, collectionUUID uuid.UUID, userEmail string) (*model.Collection, error) {
+	return s.collectionRepo.FindByIDAndUserEmail(ctx, collectionUUID, userEmail)
+}
+
+// CreateCollection 建立收藏夾，同名的收藏夾已存在時回傳 interfaces.ErrCollectionExists
+func (s *CollectionService) CreateCollection(ctx context.Context, userEmail, name, description string) (*model.Collection, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidCollectionName
+	}
+
+	// 先建立 Inbox，避免使用者自己建立的同名收藏夾佔用預設名稱
+	if _, err := s.collectionRepo.FindOrCreateDefault(ctx, userEmail); err != nil {
+		return nil, err
+	}
+
+	collection := &model.Collection{
+		UserEmail: userEmail,
+		Name:      name,
+	}
+	if description = strings.TrimSpace(description); description != "" {
+		collection.Description = &description
+	}
+
+	return s.collectionRepo.Create(ctx, collection)
+}
+
+// UpdateCollection 修改收藏夾的名稱或描述
+func (s *CollectionService) UpdateCollection(ctx context.Context, collectionUUID uuid.UUID, userEmail string, update model.CollectionUpdate) (*model.Collection, error) {
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return nil, ErrInvalidCollectionName
+		}
+		update.Name = &name
+	}
+	if update.Description != nil {
+		description := strings.TrimSpace(*update.Description)
+		update.Description = &description
+	}
+
+	return s.collectionRepo.Update(ctx, collectionUUID, userEmail, update)
+}
+
+// DeleteCollection 刪除收藏夾，其中的文章仍保留在使用者的收藏中；Inbox 不能刪除
+func (s *CollectionService) DeleteCollection(ctx context.Context, collectionUUID uuid.UUID, userEmail string) error {
+	collection, err := s.collectionRepo.FindByIDAndUserEmail(ctx, collectionUUID, userEmail)
+	if err != nil {
+		return err
+	}
+	if collection.IsDefault {
+		return ErrDefaultCollection
+	}
+
+	return s.collectionRepo.Delete(ctx, collection.ID, userEmail)
+}
+
+// ListCollectionArticles 以與文章列表相同的 keyset 分頁取得收藏夾中的文章
+func (s *CollectionService) ListCollectionArticles(ctx context.Context, collectionUUID uuid.UUID, userEmail string, query model.ArticleQuery, cursor string) (*ArticleList, error) {
+	collection, err := s.collectionRepo.FindByIDAndUserEmail(ctx, collectionUUID, userEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	query.Filter.CollectionID = &collection.ID
+	return listArticles(ctx, s.articleRepo, userEmail, query, cursor)
+}
+
+// AddArticle 將使用者的文章加到收藏夾的最後面，已在收藏夾中時不做任何事
+func (s *CollectionService) AddArticle(ctx context.Context, collectionUUID, articleUUID uuid.UUID, userEmail string) error {
+	// 收藏夾與文章都需屬於該使用者
+	collection, err := s.collectionRepo.FindByIDAndUserEmail(ctx, collectionUUID, userEmail)
+	if err != nil {
+		return err
+	}
+	article, err := s.articleRepo.FindByIDAndUserEmail(ctx, articleUUID, userEmail)
+	if err != nil {
+		return err
+	}
+
+	return s.collectionRepo.AddArticle(ctx, collection.ID, article.ID)
+}
+
+// RemoveArticle 將文章移出收藏夾，文章本身仍保留在使用者的收藏中
+func (s *CollectionService) RemoveArticle(ctx context.Context, collectionUUID, articleUUID uuid.UUID, userEmail string) error {
+	collection, err := s.collectionRepo.FindByIDAndUserEmail(ctx, collectionUUID, userEmail)
+	if err != nil {
+		return err
+	}
+
+	return s.collectionRepo.RemoveArticle(ctx, collection.ID, articleUUID)
+}
+
+// MoveArticle 將文章移到收藏夾中的第 position 個 (從 1 開始)
+func (s *CollectionService) MoveArticle(ctx context.Context, collectionUUID, articleUUID uuid.UUID, userEmail string, position int) error {
+	collection, err := s.collectionRepo.FindByIDAndUserEmail(ctx, collectionUUID, userEmail)
+	if err != nil {
+		return err
+	}
+
+	return s.collectionRepo.MoveArticle(ctx, collection.ID, articleUUID, position)
+}
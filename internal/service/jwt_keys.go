@@ -0,0 +1,221 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey 是一把 JWT 簽章金鑰；只有公鑰的金鑰僅能用來驗證 (例如輪替下線中的舊金鑰)
+type SigningKey struct {
+	KID        string
+	Method     jwt.SigningMethod
+	PrivateKey interface{} // HMAC 為 []byte，非對稱演算法為 crypto.Signer
+	PublicKey  interface{} // HMAC 為 []byte，非對稱演算法為對應的公鑰
+}
+
+// KeySet 管理多把同時有效的金鑰，以 active 金鑰簽發，並以 kid 選擇驗證金鑰
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// JWK 是 RFC 7517 定義的單把公鑰格式
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKSet 是 /.well-known/jwks.json 的回應格式
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewKeySet 建立金鑰組，activeKID 指定用來簽發 token 的金鑰
+func NewKeySet(activeKID string, keys ...*SigningKey) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*SigningKey, len(keys))}
+	for _, k := range keys {
+		if _, dup := ks.keys[k.KID]; dup {
+			return nil, fmt.Errorf("duplicate jwt key id %q", k.KID)
+		}
+		ks.keys[k.KID] = k
+	}
+
+	active, ok := ks.keys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("active jwt key %q not found", activeKID)
+	}
+	if active.PrivateKey == nil {
+		return nil, fmt.Errorf("active jwt key %q has no private key", activeKID)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+// NewHMACKey 以共享密鑰建立 HS256 金鑰
+func NewHMACKey(kid, secret string) *SigningKey {
+	return &SigningKey{
+		KID:        kid,
+		Method:     jwt.SigningMethodHS256,
+		PrivateKey: []byte(secret),
+		PublicKey:  []byte(secret),
+	}
+}
+
+// LoadSigningKey 從 PEM 檔案載入非對稱金鑰 (RS256/ES256/EdDSA 等)
+// privatePath 可為空字串，代表此金鑰只用來驗證；publicPath 為空時由私鑰推導公鑰
+func LoadSigningKey(kid, alg, privatePath, publicPath string) (*SigningKey, error) {
+	method := jwt.GetSigningMethod(alg)
+	if method == nil {
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", alg)
+	}
+	if strings.HasPrefix(alg, "HS") {
+		return nil, fmt.Errorf("jwt key %q: HMAC keys cannot be loaded from PEM files", kid)
+	}
+	if privatePath == "" && publicPath == "" {
+		return nil, fmt.Errorf("jwt key %q: private_key_file or public_key_file is required", kid)
+	}
+
+	key := &SigningKey{KID: kid, Method: method}
+
+	if privatePath != "" {
+		pem, err := os.ReadFile(privatePath)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", kid, err)
+		}
+
+		signer, err := parsePrivateKey(method, pem)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", kid, err)
+		}
+		key.PrivateKey = signer
+		key.PublicKey = signer.Public()
+	}
+
+	if publicPath != "" {
+		pem, err := os.ReadFile(publicPath)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", kid, err)
+		}
+
+		pub, err := parsePublicKey(method, pem)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", kid, err)
+		}
+		key.PublicKey = pub
+	}
+
+	return key, nil
+}
+
+// Active 回傳目前用來簽發 token 的金鑰
+func (ks *KeySet) Active() *SigningKey {
+	return ks.active
+}
+
+// Keyfunc 依 token header 的 kid 選擇驗證金鑰，並確認演算法與金鑰相符以避免演算法混淆攻擊
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	key := ks.active
+	if kid, ok := token.Header["kid"].(string); ok {
+		key, ok = ks.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown jwt key id %q", kid)
+		}
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q for key %q", token.Method.Alg(), key.KID)
+	}
+
+	return key.PublicKey, nil
+}
+
+// JWKS 回傳所有非對稱金鑰的公鑰；HMAC 共享密鑰不會被公開
+func (ks *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, k := range ks.keys {
+		jwk, ok := toJWK(k)
+		if ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+
+	return set
+}
+
+func toJWK(k *SigningKey) (JWK, bool) {
+	jwk := JWK{Kid: k.KID, Use: "sig", Alg: k.Method.Alg()}
+	enc := base64.RawURLEncoding
+
+	switch pub := k.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = enc.EncodeToString(pub.N.Bytes())
+		jwk.E = enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = enc.EncodeToString(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = enc.EncodeToString(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = enc.EncodeToString(pub)
+	default:
+		return JWK{}, false
+	}
+
+	return jwk, true
+}
+
+func parsePrivateKey(method jwt.SigningMethod, pem []byte) (crypto.Signer, error) {
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		return jwt.ParseRSAPrivateKeyFromPEM(pem)
+	case *jwt.SigningMethodECDSA:
+		return jwt.ParseECPrivateKeyFromPEM(pem)
+	case *jwt.SigningMethodEd25519:
+		key, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("ed25519 key is not a signer")
+		}
+		return signer, nil
+	}
+
+	return nil, fmt.Errorf("unsupported jwt algorithm %q", method.Alg())
+}
+
+func parsePublicKey(method jwt.SigningMethod, pem []byte) (crypto.PublicKey, error) {
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		return jwt.ParseRSAPublicKeyFromPEM(pem)
+	case *jwt.SigningMethodECDSA:
+		return jwt.ParseECPublicKeyFromPEM(pem)
+	case *jwt.SigningMethodEd25519:
+		return jwt.ParseEdPublicKeyFromPEM(pem)
+	}
+
+	return nil, fmt.Errorf("unsupported jwt algorithm %q", method.Alg())
+}
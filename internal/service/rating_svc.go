@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"deeliai/internal/interfaces"
 	"deeliai/internal/model"
@@ -12,26 +13,45 @@ import (
 
 type RatingService struct {
 	ratingRepo interfaces.RatingRepository
+	tagRepo    interfaces.TagRepository
 }
 
-func NewRatingService(repo interfaces.RatingRepository) *RatingService {
-	return &RatingService{ratingRepo: repo}
+func NewRatingService(repo interfaces.RatingRepository, tagRepo interfaces.TagRepository) *RatingService {
+	return &RatingService{ratingRepo: repo, tagRepo: tagRepo}
 }
 
-// RateArticle 為文章評分
+// RateArticle 為文章評分，tags 不為 nil 時同時將文章的標籤替換為 tags
 func (s *RatingService) RateArticle(ctx context.Context, userEmail string, articleUUID uuid.UUID, scores int, tags []string) (*model.Rating, error) {
 	if scores < 1 || scores > 5 {
 		return nil, fmt.Errorf("rating must be between 1 and 5")
 	}
 
-	rating := &model.Rating{
+	if tags != nil {
+		var err error
+		if tags, err = normalizeTags(tags); err != nil {
+			return nil, err
+		}
+	}
+
+	rating, err := s.ratingRepo.CreateOrUpdate(ctx, &model.Rating{
 		UserEmail: userEmail,
 		ArticleID: articleUUID,
 		Scores:    scores,
-		Tags:      tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 評分成功代表文章屬於該使用者，再更新標籤
+	if tags != nil {
+		if err := s.tagRepo.SetArticleTags(ctx, userEmail, articleUUID, tags); err != nil {
+			return nil, err
+		}
+		sort.Strings(tags)
+		rating.Tags = tags
 	}
 
-	return s.ratingRepo.CreateOrUpdate(ctx, rating)
+	return rating, nil
 }
 
 // GetRating 取得使用者對文章的評分
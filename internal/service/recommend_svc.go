@@ -7,30 +7,30 @@ import (
 )
 
 type RecommendService struct {
-	articleRepo interfaces.ArticleRepository
-	ratingRepo  interfaces.RatingRepository
+	pageRepo   interfaces.PageRepository
+	ratingRepo interfaces.RatingRepository
 }
 
-func NewRecommendService(articleRepo interfaces.ArticleRepository, ratingRepo interfaces.RatingRepository) *RecommendService {
+func NewRecommendService(pageRepo interfaces.PageRepository, ratingRepo interfaces.RatingRepository) *RecommendService {
 	return &RecommendService{
-		articleRepo: articleRepo,
-		ratingRepo:  ratingRepo,
+		pageRepo:   pageRepo,
+		ratingRepo: ratingRepo,
 	}
 }
 
-// GetSimpleRecommendations 實現簡單推薦演算法（結合加權標籤）
-func (s *RecommendService) GetSimpleRecommendations(ctx context.Context, userEmail string) ([]model.Article, error) {
+// GetSimpleRecommendations 實現簡單推薦演算法（結合加權標籤），以頁面為單位推薦使用者尚未收藏的網址
+func (s *RecommendService) GetSimpleRecommendations(ctx context.Context, userEmail string) ([]model.Page, error) {
 	// 從評分加權中獲取使用者偏好標籤
-	articleScores, err := s.articleRepo.ListRecommendArticles(ctx, userEmail)
+	pageScores, err := s.pageRepo.ListRecommendPages(ctx, userEmail)
 	if err != nil {
 		return nil, err
 	}
 
-	// 如果沒有高評分文章，則推薦最新的熱門文章
-	if len(articleScores) == 0 {
-		return s.articleRepo.FindLatestArticles(ctx, userEmail, 10)
+	// 如果沒有高評分文章，則推薦其他使用者最新收藏的頁面
+	if len(pageScores) == 0 {
+		return s.pageRepo.FindLatestPages(ctx, userEmail, 10)
 	}
 
 	// 呼叫新的 FindRelatedArticles 函式
-	return articleScores, nil
+	return pageScores, nil
 }
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"deeliai/internal/scraper"
+)
+
+// ErrorClass 描述爬取錯誤是否值得重試
+type ErrorClass string
+
+const (
+	ErrorClassTransient   ErrorClass = "transient"    // 暫時性錯誤 (5xx、逾時、連線中斷)，以指數退避重試
+	ErrorClassRateLimited ErrorClass = "rate_limited" // 被目標網站限流 (429)，依 Retry-After 延後重試
+	ErrorClassPermanent   ErrorClass = "permanent"    // 重試也不會成功 (404、不支援的內容類型)，直接放棄
+)
+
+// ScrapeError 是帶有分類資訊的爬取錯誤
+type ScrapeError struct {
+	Class      ErrorClass
+	StatusCode int           // HTTP 狀態碼，非 HTTP 錯誤時為 0
+	RetryAfter time.Duration // 目標網站要求的最短等待時間
+	Err        error
+}
+
+func (e *ScrapeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ScrapeError) Unwrap() error {
+	return e.Err
+}
+
+// NewHTTPStatusError 依 HTTP 狀態碼建立分類好的錯誤
+func NewHTTPStatusError(statusCode int, status string, header http.Header) *ScrapeError {
+	serr := &ScrapeError{
+		StatusCode: statusCode,
+		Err:        fmt.Errorf("status code error: %d %s", statusCode, status),
+	}
+
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		serr.Class = ErrorClassRateLimited
+		serr.RetryAfter = parseRetryAfter(header.Get("Retry-After"), time.Now())
+	case statusCode == http.StatusRequestTimeout, statusCode == http.StatusTooEarly, statusCode >= 500:
+		serr.Class = ErrorClassTransient
+		serr.RetryAfter = parseRetryAfter(header.Get("Retry-After"), time.Now())
+	default:
+		serr.Class = ErrorClassPermanent
+	}
+
+	return serr
+}
+
+// ClassifyScrapeError 將任意錯誤轉為 ScrapeError；無法判斷的錯誤一律視為暫時性錯誤
+func ClassifyScrapeError(err error) *ScrapeError {
+	var serr *ScrapeError
+	if errors.As(err, &serr) {
+		return serr
+	}
+
+	// 內容不符或過大、轉址過多、目標位址或 robots.txt 不允許，重試也不會成功
+	if errors.Is(err, scraper.ErrUnsupportedContentType) || errors.Is(err, scraper.ErrBodyTooLarge) || errors.Is(err, scraper.ErrTooManyRedirects) ||
+		errors.Is(err, scraper.ErrUnsupportedScheme) || errors.Is(err, scraper.ErrBlockedAddress) || errors.Is(err, scraper.ErrDisallowedByRobots) {
+		return &ScrapeError{Class: ErrorClassPermanent, Err: err}
+	}
+
+	// 網域不存在，重試也不會成功
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return &ScrapeError{Class: ErrorClassPermanent, Err: err}
+	}
+
+	return &ScrapeError{Class: ErrorClassTransient, Err: err}
+}
+
+// RetryPolicy 決定爬取失敗後是否重試以及何時重試
+type RetryPolicy struct {
+	MaxAttempts int           // 含第一次爬取的總嘗試次數
+	BaseDelay   time.Duration // 第一次重試前的等待時間，之後每次加倍
+	MaxDelay    time.Duration // 單次等待時間上限
+}
+
+// DefaultRetryPolicy 等同舊制的「最多重試三次」
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   time.Minute,
+	MaxDelay:    6 * time.Hour,
+}
+
+// NextAttempt 回傳下一次重試的時間；attempts 為已失敗的次數 (含這一次)
+// 回傳 false 代表應該放棄 (永久性錯誤或已用完重試次數)
+func (p RetryPolicy) NextAttempt(attempts int, serr *ScrapeError, now time.Time) (time.Time, bool) {
+	if serr.Class == ErrorClassPermanent || attempts >= p.MaxAttempts {
+		return time.Time{}, false
+	}
+
+	delay := p.backoff(attempts)
+
+	// 目標網站指定的等待時間優先於退避時間
+	if serr.RetryAfter > delay {
+		delay = serr.RetryAfter
+	}
+
+	return now.Add(delay), true
+}
+
+// backoff 計算指數退避並加上 jitter，避免大量失敗的任務在同一時間點重試
+func (p RetryPolicy) backoff(attempts int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempts-1))
+	if delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	// equal jitter：保留一半的延遲，另一半隨機
+	half := delay / 2
+	return time.Duration(half + rand.Float64()*half)
+}
+
+// parseRetryAfter 解析 Retry-After header，支援秒數與 HTTP 日期兩種格式
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+
+	return 0
+}
@@ -1,90 +1,316 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"deeliai/internal/interfaces"
-	"fmt"
+	"deeliai/internal/model"
+	"deeliai/internal/scraper"
+	"deeliai/internal/urlnorm"
+	"errors"
 	"log"
+	"math/rand"
 	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/google/uuid"
 )
 
 type ScrapeService struct {
-	articleRepo interfaces.ArticleRepository
+	pageRepo    interfaces.PageRepository
+	attemptRepo interfaces.ScrapeAttemptRepository
+	retryPolicy RetryPolicy
+	fetcher     *scraper.Fetcher
+	politeness  *scraper.Politeness
+	extractors  *scraper.ExtractorRegistry
+	contentRepo interfaces.PageContentRepository
+
+	// 預覽圖快取，imageStore 為 nil 時不下載圖片
+	imageStore      interfaces.BlobStore
+	thumbnailWidth  int
+	thumbnailHeight int
+}
+
+// scrapeResult 是單次爬取的結果；即使爬取失敗，也會盡量帶回 HTTP 狀態碼與最終 URL
+type scrapeResult struct {
+	Metadata   *model.ArticleMetadata
+	Content    *scraper.ExtractedContent // 找不到內文時為 nil
+	StatusCode int
+	FinalURL   string
 }
 
-// NewScrapeService 接受 worker 數量作為參數
-func NewScrapeService(repo interfaces.ArticleRepository) *ScrapeService {
+// NewScrapeService 接受爬取失敗時的重試策略、抓取網頁用的 Fetcher、對目標主機的禮貌規則與依網站挑選的 extractor
+func NewScrapeService(repo interfaces.PageRepository, attemptRepo interfaces.ScrapeAttemptRepository, contentRepo interfaces.PageContentRepository, retryPolicy RetryPolicy, fetcher *scraper.Fetcher, politeness *scraper.Politeness, extractors *scraper.ExtractorRegistry) *ScrapeService {
 	return &ScrapeService{
-		articleRepo: repo,
+		pageRepo:    repo,
+		attemptRepo: attemptRepo,
+		contentRepo: contentRepo,
+		retryPolicy: retryPolicy,
+		fetcher:     fetcher,
+		politeness:  politeness,
+		extractors:  extractors,
 	}
 }
 
-// ProcessScrapeTask 處理單個爬取任務
-func (w *ScrapeService) ProcessScrapeTask(ctx context.Context, articleID string) {
-	id, err := uuid.Parse(articleID)
+// EnableImageCache 啟用預覽圖快取：爬取成功後下載 og:image，縮圖後存入 store
+func (w *ScrapeService) EnableImageCache(store interfaces.BlobStore, maxWidth, maxHeight int) {
+	w.imageStore = store
+	w.thumbnailWidth = maxWidth
+	w.thumbnailHeight = maxHeight
+}
+
+// ProcessScrapeTask 處理單個爬取任務，任務內容是頁面 ID；同一頁面不論被多少使用者收藏都只爬取一次
+func (w *ScrapeService) ProcessScrapeTask(ctx context.Context, pageID string) {
+	id, err := uuid.Parse(pageID)
 	if err != nil {
-		log.Printf("Invalid article ID in queue: %s", articleID)
+		log.Printf("Invalid page ID in queue: %s", pageID)
 		return
 	}
 
 	// 這裡需要從資料庫取回 URL，由於我們直接傳 ID，所以需要先查詢一次
-	article, err := w.articleRepo.FindByID(ctx, id)
+	page, err := w.pageRepo.FindByID(ctx, id)
+	if err != nil {
+		log.Printf("Page not found for ID: %s", pageID)
+		return
+	}
+
+	// 佇列可能重複送達 (例如 at-least-once 的 redis/postgres 佇列)，已爬取成功的頁面不再重爬
+	if page.ScrapeStatus == model.ScrapeStatusSuccess {
+		log.Printf("Page %s already scraped, skipping", pageID)
+		return
+	}
+
+	// 遵守 robots.txt 並限制對同一主機的頻率，主機忙碌時延後任務而不是視為失敗
+	release, err := w.politeness.Acquire(ctx, page.URL)
+	var throttled *scraper.ThrottledError
+	if errors.As(err, &throttled) {
+		w.deferScrape(ctx, page, throttled)
+		return
+	}
 	if err != nil {
-		log.Printf("Article not found for ID: %s", articleID)
+		w.recordAttempt(ctx, id, &scrapeResult{}, err, 0)
+		w.handleScrapeFailure(ctx, page, err)
 		return
 	}
+	defer release()
 
-	title, desc, img, err := w.scrapeMetadata(article.URL)
+	start := time.Now()
+	result, err := w.scrapeMetadata(ctx, page.URL)
+	w.recordAttempt(ctx, id, result, err, time.Since(start))
 	if err != nil {
-		log.Printf("Failed to scrape URL %s: %v", article.URL, err)
-		// 爬取失敗，標記為失敗並增加重試次數
-		if err := w.articleRepo.MarkScrapeFailed(ctx, id); err != nil {
-			log.Printf("Failed to mark scrape as failed: %v", err)
-		}
+		w.handleScrapeFailure(ctx, page, err)
 		return
 	}
 
 	// 爬取成功，更新資料庫
-	if err := w.articleRepo.UpdateMetadata(ctx, id, title, desc, img); err != nil {
-		log.Printf("Failed to update article metadata: %v", err)
-	} else {
-		log.Printf("Successfully scraped and updated article ID: %s", articleID)
+	if err := w.pageRepo.UpdateMetadata(ctx, id, result.Metadata); err != nil {
+		log.Printf("Failed to update page metadata: %v", err)
+		return
+	}
+	log.Printf("Successfully scraped and updated page ID: %s", pageID)
+
+	// 內文只是附加資訊，儲存失敗不影響 metadata
+	if result.Content != nil {
+		content := &model.PageContent{
+			PageID:             id,
+			ContentHTML:        result.Content.HTML,
+			ContentText:        result.Content.Text,
+			WordCount:          result.Content.WordCount,
+			ReadingTimeMinutes: result.Content.ReadingTimeMinutes,
+		}
+		if err := w.contentRepo.Upsert(ctx, content); err != nil {
+			log.Printf("Failed to save page content for %s: %v", pageID, err)
+		}
+	}
+
+	// 預覽圖同樣是附加資訊，下載失敗時前端仍可使用原始的 image_url
+	if w.imageStore != nil && result.Metadata.ImageURL != "" {
+		if err := w.cacheImage(ctx, id, result.Metadata.ImageURL); err != nil {
+			log.Printf("Failed to cache preview image for %s: %v", pageID, err)
+		}
+	}
+}
+
+// cacheImage 下載預覽圖、產生縮圖並存入 BlobStore
+func (w *ScrapeService) cacheImage(ctx context.Context, pageID uuid.UUID, imageURL string) error {
+	image, err := w.fetcher.Fetch(ctx, imageURL, scraper.ImageAcceptTypes...)
+	if err != nil {
+		return err
+	}
+	if image.StatusCode != http.StatusOK {
+		return NewHTTPStatusError(image.StatusCode, image.Status, image.Header)
+	}
+
+	thumbnail, err := scraper.MakeThumbnail(image.Body, w.thumbnailWidth, w.thumbnailHeight)
+	if err != nil {
+		return err
+	}
+
+	key := ThumbnailKey(pageID)
+	if err := w.imageStore.Put(ctx, key, bytes.NewReader(thumbnail)); err != nil {
+		return err
+	}
+
+	return w.pageRepo.SetThumbnailKey(ctx, pageID, key)
+}
+
+// ThumbnailKey 回傳頁面縮圖在 BlobStore 中的 key
+func ThumbnailKey(pageID uuid.UUID) string {
+	return "thumbnails/" + pageID.String() + ".jpg"
+}
+
+// handleScrapeFailure 依錯誤分類與重試策略決定延後重試或永久放棄
+func (w *ScrapeService) handleScrapeFailure(ctx context.Context, page *model.Page, err error) {
+	serr := ClassifyScrapeError(err)
+	attempts := page.RetryCount + 1
+
+	nextAttemptAt, retry := w.retryPolicy.NextAttempt(attempts, serr, time.Now())
+	if !retry {
+		log.Printf("Giving up scraping URL %s after %d attempt(s) (%s): %v", page.URL, attempts, serr.Class, err)
+		if err := w.pageRepo.MarkScrapeFailedPermanently(ctx, page.ID, err.Error()); err != nil {
+			log.Printf("Failed to mark scrape as permanently failed: %v", err)
+		}
+		return
+	}
+
+	log.Printf("Failed to scrape URL %s (%s), retrying at %s: %v", page.URL, serr.Class, nextAttemptAt.Format(time.RFC3339), err)
+	// 爬取失敗，標記為失敗並增加重試次數
+	if err := w.pageRepo.MarkScrapeFailed(ctx, page.ID, nextAttemptAt, err.Error()); err != nil {
+		log.Printf("Failed to mark scrape as failed: %v", err)
+	}
+}
+
+// deferScrape 將任務延後到主機有空時再爬取，不計入重試次數
+func (w *ScrapeService) deferScrape(ctx context.Context, page *model.Page, throttled *scraper.ThrottledError) {
+	// 加上 jitter，避免同一主機的大量任務在同一時間點再次擠在一起
+	delay := throttled.RetryAfter + time.Duration(rand.Int63n(int64(throttled.RetryAfter)+1))
+	nextAttemptAt := time.Now().Add(delay)
+
+	log.Printf("Deferring scrape of URL %s until %s: %v", page.URL, nextAttemptAt.Format(time.RFC3339), throttled)
+	if err := w.pageRepo.MarkScrapeDeferred(ctx, page.ID, nextAttemptAt); err != nil {
+		log.Printf("Failed to mark scrape as deferred: %v", err)
+	}
+}
+
+// recordAttempt 將這次爬取的結果寫入 scrape_attempts，寫入失敗不影響爬取流程
+func (w *ScrapeService) recordAttempt(ctx context.Context, pageID uuid.UUID, result *scrapeResult, scrapeErr error, duration time.Duration) {
+	attempt := &model.ScrapeAttempt{
+		PageID:     pageID,
+		Success:    scrapeErr == nil,
+		DurationMS: int(duration.Milliseconds()),
+	}
+
+	if result.StatusCode != 0 {
+		attempt.HTTPStatus = &result.StatusCode
+	}
+	if result.FinalURL != "" {
+		attempt.FinalURL = &result.FinalURL
+	}
+	if scrapeErr != nil {
+		class := string(ClassifyScrapeError(scrapeErr).Class)
+		message := scrapeErr.Error()
+		attempt.ErrorClass = &class
+		attempt.ErrorMessage = &message
+	}
+
+	if err := w.attemptRepo.Create(ctx, attempt); err != nil {
+		log.Printf("Failed to record scrape attempt for page %s: %v", pageID.String(), err)
 	}
 }
 
-// scrapeMetadata 實際的爬取邏輯，使用 goquery
-func (w *ScrapeService) scrapeMetadata(url string) (title, description, imageURL string, err error) {
-	resp, err := http.Get(url)
+// scrapeMetadata 實際的爬取邏輯，由 Fetcher 抓取後依文件類型解析；HTML 使用 goquery
+func (w *ScrapeService) scrapeMetadata(ctx context.Context, url string) (*scrapeResult, error) {
+	result := &scrapeResult{}
+
+	page, err := w.fetcher.Fetch(ctx, url, scraper.DocumentAcceptTypes...)
+	if page != nil {
+		result.StatusCode = page.StatusCode
+		result.FinalURL = page.FinalURL
+	}
 	if err != nil {
-		return "", "", "", err
+		return result, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return "", "", "", fmt.Errorf("status code error: %d %s", resp.StatusCode, resp.Status)
+	if page.StatusCode != http.StatusOK {
+		return result, NewHTTPStatusError(page.StatusCode, page.Status, page.Header)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	documentType := scraper.DocumentType(page.ContentType)
+	var meta *model.ArticleMetadata
+	switch documentType {
+	case model.DocumentTypePDF:
+		if meta, result.Content, err = scraper.ExtractPDF(page); err != nil {
+			// 檔案損毀或加密，重試也不會成功
+			return result, &ScrapeError{Class: ErrorClassPermanent, StatusCode: page.StatusCode, Err: err}
+		}
+	case model.DocumentTypeImage:
+		meta = scraper.ExtractImage(page)
+	case model.DocumentTypeText:
+		meta, result.Content = scraper.ExtractText(page)
+	default:
+		if meta, result.Content, err = w.extractHTML(page); err != nil {
+			return result, err
+		}
+	}
+
+	meta.DocumentType = documentType
+	// 頁面宣告的 canonical 網址也用來比對重複收藏，例如先存短網址、之後再存原始網址
+	if meta.CanonicalLink != "" {
+		meta.CanonicalURL, _ = urlnorm.Normalize(meta.CanonicalLink)
+	}
+	result.Metadata = fitMetadataColumns(meta)
+
+	return result, nil
+}
+
+// extractHTML 依最終網址挑選網站專屬的 extractor，失敗時退回通用規則 (OpenGraph、Twitter Card、JSON-LD、一般 HTML 標籤)
+func (w *ScrapeService) extractHTML(page *scraper.FetchResult) (*model.ArticleMetadata, *scraper.ExtractedContent, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.Body))
 	if err != nil {
-		return "", "", "", err
+		return nil, nil, err
 	}
 
-	// 優先抓取 OpenGraph Metadata
-	title = doc.Find("meta[property='og:title']").AttrOr("content", "")
-	description = doc.Find("meta[property='og:description']").AttrOr("content", "")
-	imageURL = doc.Find("meta[property='og:image']").AttrOr("content", "")
+	extractor := w.extractors.For(page.FinalURL)
+	meta, err := extractor.Extract(page, doc)
+	if err != nil {
+		log.Printf("Extractor %s failed for %s, falling back to generic: %v", extractor.Name(), page.FinalURL, err)
+		if meta, err = w.extractors.Fallback().Extract(page, doc); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 擷取內文會移除 doc 中的節點，必須在 metadata 之後
+	return meta, scraper.ExtractContent(doc, page.FinalURL), nil
+}
+
+// 對應 pages 資料表的欄位長度 (VARCHAR 以字元計算)
+const (
+	maxTitleLength = 255
+	maxURLLength   = 2048
+)
 
-	// 若 OpenGraph 找不到，退回抓取一般 HTML 標籤
-	if title == "" {
-		title = doc.Find("title").Text()
+// fitMetadataColumns 讓 metadata 符合資料表欄位長度，避免過長的值讓 UpdateMetadata 失敗
+// 標題依字元 (rune) 截斷，不會切壞多位元組字元；網址截斷後就無法使用，過長時直接捨棄
+func fitMetadataColumns(meta *model.ArticleMetadata) *model.ArticleMetadata {
+	meta.Title = truncateRunes(meta.Title, maxTitleLength)
+	for _, u := range []*string{&meta.ImageURL, &meta.FaviconURL, &meta.CanonicalLink, &meta.CanonicalURL, &meta.OEmbedURL} {
+		if utf8.RuneCountInString(*u) > maxURLLength {
+			*u = ""
+		}
 	}
-	if description == "" {
-		description = doc.Find("meta[name='description']").AttrOr("content", "")
+
+	return meta
+}
+
+// truncateRunes 將字串截斷為最多 n 個字元，截斷時以 "…" 結尾
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
 	}
-	// Image 暫不退回，因為一般 img 標籤可能不適合作為預覽圖
 
-	return title, description, imageURL, nil
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
 }
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"deeliai/internal/interfaces"
+	"deeliai/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// maxTagLength 是標籤名稱正規化後的字元數上限，與 tags.name 欄位長度一致
+const maxTagLength = 64
+
+// ErrInvalidTag 表示標籤名稱正規化後為空或超過長度上限
+var ErrInvalidTag = errors.New("tag name must be 1-64 characters")
+
+type TagService struct {
+	tagRepo     interfaces.TagRepository
+	articleRepo interfaces.ArticleRepository
+}
+
+func NewTagService(tagRepo interfaces.TagRepository, articleRepo interfaces.ArticleRepository) *TagService {
+	return &TagService{
+		tagRepo:     tagRepo,
+		articleRepo: articleRepo,
+	}
+}
+
+// normalizeTag 去除前後空白、將連續空白合併為一個並轉為小寫，讓 "Go"、" go " 視為同一個標籤
+func normalizeTag(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// normalizeTags 正規化並去除重複的標籤，保留第一次出現的順序
+func normalizeTags(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		name = normalizeTag(name)
+		if name == "" || utf8.RuneCountInString(name) > maxTagLength {
+			return nil, ErrInvalidTag
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+
+	return result, nil
+}
+
+// ListTags 取得使用者所有標籤與各自的文章數
+func (s *TagService) ListTags(ctx context.Context, userEmail string) ([]model.TagCount, error) {
+	return s.tagRepo.ListByUserEmail(ctx, userEmail)
+}
+
+// ListArticleTags 取得使用者文章的標籤
+func (s *TagService) ListArticleTags(ctx context.Context, articleUUID uuid.UUID, userEmail string) ([]model.Tag, error) {
+	// 先確認文章屬於該使用者
+	article, err := s.articleRepo.FindByIDAndUserEmail(ctx, articleUUID, userEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tagRepo.ListByArticleID(ctx, article.ID)
+}
+
+// AddArticleTags 為文章加上標籤，不需要先評分；回傳文章加上後的所有標籤
+func (s *TagService) AddArticleTags(ctx context.Context, articleUUID uuid.UUID, userEmail string, names []string) ([]model.Tag, error) {
+	names, err := normalizeTags(names)
+	if err != nil {
+		return nil, err
+	}
+
+	article, err := s.articleRepo.FindByIDAndUserEmail(ctx, articleUUID, userEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.AddToArticle(ctx, userEmail, article.ID, names); err != nil {
+		return nil, err
+	}
+
+	return s.tagRepo.ListByArticleID(ctx, article.ID)
+}
+
+// RemoveArticleTag 從文章移除標籤，標籤本身保留；文章或標籤不存在時回傳 sql.ErrNoRows
+func (s *TagService) RemoveArticleTag(ctx context.Context, articleUUID, tagUUID uuid.UUID, userEmail string) error {
+	article, err := s.articleRepo.FindByIDAndUserEmail(ctx, articleUUID, userEmail)
+	if err != nil {
+		return err
+	}
+
+	return s.tagRepo.RemoveFromArticle(ctx, article.ID, tagUUID)
+}
+
+// RenameTag 修改標籤名稱，所有使用該標籤的文章都會跟著改變；已有同名標籤時兩者合併
+func (s *TagService) RenameTag(ctx context.Context, userEmail string, tagUUID uuid.UUID, name string) (*model.Tag, error) {
+	names, err := normalizeTags([]string{name})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tagRepo.Rename(ctx, userEmail, tagUUID, names[0])
+}
+
+// DeleteTag 刪除標籤並從所有文章移除
+func (s *TagService) DeleteTag(ctx context.Context, userEmail string, tagUUID uuid.UUID) error {
+	return s.tagRepo.Delete(ctx, userEmail, tagUUID)
+}
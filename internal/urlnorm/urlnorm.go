@@ -0,0 +1,135 @@
+// Package urlnorm 將指向同一份內容的不同網址寫法正規化為同一個字串，用於判斷重複收藏
+package urlnorm
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidURL = errors.New("invalid url")
+
+// trackingParams 是不影響內容的追蹤參數
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "gbraid": true, "wbraid": true, "msclkid": true,
+	"yclid": true, "twclid": true, "igshid": true, "mc_cid": true, "mc_eid": true, "_ga": true,
+	"_hsenc": true, "_hsmi": true, "mkt_tok": true, "ref_src": true, "ref_url": true,
+	"spm": true, "vero_id": true, "oly_anon_id": true, "oly_enc_id": true,
+}
+
+// hostTrackingParams 是只在特定網站 (含子網域) 才是追蹤用途的參數，例如 YouTube 與 Spotify 分享連結的 si
+// 這些名稱在其他網站可能是內容參數，不能一律去掉
+var hostTrackingParams = map[string][]string{
+	"youtube.com": {"si"},
+	"youtu.be":    {"si"},
+	"spotify.com": {"si"},
+}
+
+// trackingPrefixes 是以這些字首開頭的追蹤參數，例如 utm_source
+var trackingPrefixes = []string{"utm_", "pk_", "hsa_"}
+
+// hostPrefixes 是指向同一網站的行動版或 www 子網域
+var hostPrefixes = []string{"www.", "m.", "mobile.", "amp."}
+
+// Normalize 回傳網址的正規化形式：
+//   - http 與 https 視為相同，一律使用 https
+//   - 主機名稱轉小寫，去掉預設 port 與 www.、m. 等子網域
+//   - 去掉 fragment、utm_* 等追蹤參數 (以及特定網站的 si 等參數)，其餘參數依名稱排序
+//   - 去掉路徑結尾的斜線
+func Normalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" || u.Host == "" {
+		return "", ErrInvalidURL
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	port := u.Port()
+	if port == "80" || port == "443" {
+		port = ""
+	}
+	for _, prefix := range hostPrefixes {
+		// 只去掉子網域，不能把 m.com 這種網域本身去掉
+		if strings.HasPrefix(host, prefix) && strings.Contains(host[len(prefix):], ".") {
+			host = host[len(prefix):]
+			break
+		}
+	}
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6
+	}
+
+	path := u.EscapedPath()
+	for strings.Contains(path, "//") {
+		path = strings.ReplaceAll(path, "//", "/")
+	}
+	path = strings.TrimSuffix(path, "/")
+
+	normalized := "https://" + host + path
+	if query := normalizeQuery(u.Query(), siteTrackingParams(host)); query != "" {
+		normalized += "?" + query
+	}
+
+	return normalized, nil
+}
+
+// normalizeQuery 去掉追蹤參數與 siteParams 並依名稱排序，同名參數保留原本順序
+func normalizeQuery(values url.Values, siteParams []string) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !isTrackingParam(key) && !slices.Contains(siteParams, strings.ToLower(key)) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		for _, value := range values[key] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(key))
+			if value != "" {
+				b.WriteByte('=')
+				b.WriteString(url.QueryEscape(value))
+			}
+		}
+	}
+	return b.String()
+}
+
+// siteTrackingParams 回傳 host (已去掉 www. 等字首，可能帶有 port) 所屬網站的追蹤參數
+func siteTrackingParams(host string) []string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	for site, params := range hostTrackingParams {
+		if host == site || strings.HasSuffix(host, "."+site) {
+			return params
+		}
+	}
+	return nil
+}
+
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	if trackingParams[key] {
+		return true
+	}
+	for _, prefix := range trackingPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
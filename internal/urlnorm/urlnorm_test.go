@@ -0,0 +1,95 @@
+package urlnorm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"already normalized", "https://example.com/a", "https://example.com/a"},
+		{"surrounding spaces", "  https://example.com/a  ", "https://example.com/a"},
+
+		// http 與 https 視為同一份內容
+		{"http upgraded to https", "http://example.com/a", "https://example.com/a"},
+		{"scheme is case insensitive", "HTTPS://example.com/a", "https://example.com/a"},
+
+		// 主機名稱與 port
+		{"host lowercased", "https://Example.COM/a", "https://example.com/a"},
+		{"trailing dot in host", "https://example.com./a", "https://example.com/a"},
+		{"default http port", "http://example.com:80/a", "https://example.com/a"},
+		{"default https port", "https://example.com:443/a", "https://example.com/a"},
+		{"non-default port kept", "https://example.com:8443/a", "https://example.com:8443/a"},
+		{"ipv4", "http://127.0.0.1:80/", "https://127.0.0.1"},
+		{"ipv6", "http://[::1]/a", "https://[::1]/a"},
+		{"ipv6 with port", "http://[::1]:8080/a", "https://[::1]:8080/a"},
+		{"ipv6 lowercased without default port", "https://[2001:DB8::1]:443/", "https://[2001:db8::1]"},
+
+		// 行動版與 www 子網域
+		{"www stripped", "https://www.example.com/a", "https://example.com/a"},
+		{"m stripped", "https://m.example.com/a", "https://example.com/a"},
+		{"mobile stripped", "https://mobile.example.com/a", "https://example.com/a"},
+		{"amp stripped", "https://amp.example.com/a", "https://example.com/a"},
+		{"only one prefix stripped", "https://www.m.example.com/a", "https://m.example.com/a"},
+		{"registrable domain kept", "https://m.com/a", "https://m.com/a"},
+		{"www domain kept", "https://www.com/a", "https://www.com/a"},
+		{"other subdomains kept", "https://blog.example.com/a", "https://blog.example.com/a"},
+
+		// 路徑
+		{"trailing slash", "https://example.com/a/", "https://example.com/a"},
+		{"root path", "https://example.com/", "https://example.com"},
+		{"duplicate slashes", "https://example.com//a///b/", "https://example.com/a/b"},
+		{"escaped path kept", "https://example.com/a%20b", "https://example.com/a%20b"},
+		{"fragment dropped", "https://example.com/a#section", "https://example.com/a"},
+
+		// 查詢參數
+		{"params sorted", "https://example.com/a?b=2&a=1", "https://example.com/a?a=1&b=2"},
+		{"repeated params keep order", "https://example.com/a?t=2&t=1", "https://example.com/a?t=2&t=1"},
+		{"param without value", "https://example.com/a?flag", "https://example.com/a?flag"},
+		{"tracking params dropped", "https://example.com/a?id=1&fbclid=x&gclid=y&utm_source=z&UTM_Medium=w", "https://example.com/a?id=1"},
+		{"only tracking params", "https://example.com/a?utm_source=z", "https://example.com/a"},
+
+		// si 只在 YouTube、Spotify 是分享追蹤參數
+		{"si kept on other sites", "https://example.com/search?si=1", "https://example.com/search?si=1"},
+		{"si kept on lookalike host", "https://notyoutube.com/watch?si=1", "https://notyoutube.com/watch?si=1"},
+		{"si dropped on youtube", "https://www.youtube.com/watch?v=abc&si=xyz", "https://youtube.com/watch?v=abc"},
+		{"si dropped on youtube subdomain", "https://music.youtube.com/watch?v=abc&si=xyz", "https://music.youtube.com/watch?v=abc"},
+		{"si dropped on youtu.be", "https://youtu.be/abc?si=xyz", "https://youtu.be/abc"},
+		{"si dropped on spotify", "https://open.spotify.com/track/1?si=xyz", "https://open.spotify.com/track/1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.url)
+			if err != nil {
+				t.Fatalf("Normalize(%q) returned error: %v", tt.url, err)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"example.com/a",
+		"/a/b",
+		"ftp://example.com/a",
+		"mailto:someone@example.com",
+		"javascript:alert(1)",
+		"https://",
+		"http://[::1",
+		"http://%zz",
+	}
+	for _, rawURL := range tests {
+		if got, err := Normalize(rawURL); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("Normalize(%q) = %q, %v, want ErrInvalidURL", rawURL, got, err)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_email VARCHAR(255) NOT NULL,
+    refresh_token_hash VARCHAR(64) NOT NULL,
+    previous_refresh_token_hashes TEXT[] NOT NULL DEFAULT '{}',
+    user_agent VARCHAR(512) DEFAULT '',
+    ip_address VARCHAR(64) DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sessions_user_email ON sessions(user_email);
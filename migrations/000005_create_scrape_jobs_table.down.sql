@@ -0,0 +1 @@
+DROP TABLE scrape_jobs;
\ No newline at end of file
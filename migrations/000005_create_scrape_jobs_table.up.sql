@@ -0,0 +1,14 @@
+CREATE TABLE scrape_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    payload TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    generation INT NOT NULL DEFAULT 0,
+    visible_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_by VARCHAR(255) DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- 同一個任務在佇列中只保留一筆，避免排程器重複排入
+CREATE UNIQUE INDEX idx_scrape_jobs_payload ON scrape_jobs(payload);
+CREATE INDEX idx_scrape_jobs_visible_at ON scrape_jobs(visible_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_articles_status_next_attempt;
+CREATE INDEX idx_articles_status_retry ON articles(scrape_status, retry_count);
+
+UPDATE articles SET scrape_status = 'failed' WHERE scrape_status = 'failed_permanent';
+ALTER TABLE articles DROP COLUMN next_attempt_at;
@@ -0,0 +1,8 @@
+ALTER TABLE articles ADD COLUMN next_attempt_at TIMESTAMPTZ;
+
+-- 舊制最多重試三次，已用完次數的文章直接視為永久失敗
+UPDATE articles SET scrape_status = 'failed_permanent' WHERE scrape_status = 'failed' AND retry_count >= 3;
+UPDATE articles SET next_attempt_at = now() WHERE scrape_status = 'failed';
+
+DROP INDEX IF EXISTS idx_articles_status_retry;
+CREATE INDEX idx_articles_status_next_attempt ON articles(scrape_status, next_attempt_at);
@@ -0,0 +1,2 @@
+ALTER TABLE articles DROP COLUMN last_error;
+DROP TABLE scrape_attempts;
@@ -0,0 +1,20 @@
+CREATE TABLE scrape_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    article_id UUID NOT NULL,
+    success BOOLEAN NOT NULL,
+    http_status INT,
+    error_class VARCHAR(20),
+    error_message TEXT,
+    duration_ms INT NOT NULL DEFAULT 0,
+    final_url VARCHAR(2048),
+    attempted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_article
+        FOREIGN KEY(article_id)
+        REFERENCES articles(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_scrape_attempts_article_id ON scrape_attempts(article_id, attempted_at DESC);
+
+ALTER TABLE articles ADD COLUMN last_error TEXT;
@@ -0,0 +1,10 @@
+ALTER TABLE articles
+    DROP COLUMN author,
+    DROP COLUMN site_name,
+    DROP COLUMN favicon_url,
+    DROP COLUMN canonical_link,
+    DROP COLUMN oembed_url,
+    DROP COLUMN language,
+    DROP COLUMN keywords,
+    DROP COLUMN published_at,
+    DROP COLUMN modified_at;
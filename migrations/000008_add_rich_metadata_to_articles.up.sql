@@ -0,0 +1,10 @@
+ALTER TABLE articles
+    ADD COLUMN author TEXT,
+    ADD COLUMN site_name TEXT,
+    ADD COLUMN favicon_url VARCHAR(2048),
+    ADD COLUMN canonical_link VARCHAR(2048),
+    ADD COLUMN oembed_url VARCHAR(2048),
+    ADD COLUMN language VARCHAR(35),
+    ADD COLUMN keywords TEXT[],
+    ADD COLUMN published_at TIMESTAMPTZ,
+    ADD COLUMN modified_at TIMESTAMPTZ;
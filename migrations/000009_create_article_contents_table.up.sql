@@ -0,0 +1,13 @@
+CREATE TABLE article_contents (
+    article_id UUID PRIMARY KEY,
+    content_html TEXT NOT NULL DEFAULT '',
+    content_text TEXT NOT NULL DEFAULT '',
+    word_count INT NOT NULL DEFAULT 0,
+    reading_time_minutes INT NOT NULL DEFAULT 0,
+    extracted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_article
+        FOREIGN KEY(article_id)
+        REFERENCES articles(id)
+        ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE articles ADD COLUMN thumbnail_key VARCHAR(255);
@@ -0,0 +1 @@
+ALTER TABLE articles DROP COLUMN extras;
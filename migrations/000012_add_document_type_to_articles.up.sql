@@ -0,0 +1,4 @@
+ALTER TABLE articles ADD COLUMN document_type VARCHAR(20);
+
+-- 既有成功爬取的文章都是 HTML
+UPDATE articles SET document_type = 'html' WHERE scrape_status = 'success';
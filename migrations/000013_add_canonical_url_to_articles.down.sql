@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS articles_user_email_scraped_canonical_url_idx;
+ALTER TABLE articles DROP COLUMN scraped_canonical_url;
+DROP INDEX IF EXISTS articles_user_email_canonical_url_key;
+ALTER TABLE articles DROP COLUMN canonical_url;
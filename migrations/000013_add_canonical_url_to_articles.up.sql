@@ -0,0 +1,9 @@
+-- canonical_url 是使用者提交網址的正規化形式，用於偵測同一使用者重複收藏
+-- 既有文章維持 NULL (不受唯一索引限制)，避免已存在的重複資料讓 migration 失敗
+-- 可以在執行 000014 前以 go run ./cmd/backfill-canonical-url 依 urlnorm 補上 (選擇性，不會刪除重複的文章)
+ALTER TABLE articles ADD COLUMN canonical_url VARCHAR(2048);
+CREATE UNIQUE INDEX articles_user_email_canonical_url_key ON articles (user_email, canonical_url);
+
+-- scraped_canonical_url 是爬取到的 link[rel=canonical] 正規化後的值，同一頁面可能由不同網址收藏，不設唯一限制
+ALTER TABLE articles ADD COLUMN scraped_canonical_url VARCHAR(2048);
+CREATE INDEX articles_user_email_scraped_canonical_url_idx ON articles (user_email, scraped_canonical_url);
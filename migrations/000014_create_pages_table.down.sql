@@ -0,0 +1,76 @@
+ALTER TABLE articles
+    ADD COLUMN title VARCHAR(255) DEFAULT '',
+    ADD COLUMN description TEXT DEFAULT '',
+    ADD COLUMN image_url VARCHAR(2048) DEFAULT '',
+    ADD COLUMN author TEXT,
+    ADD COLUMN site_name TEXT,
+    ADD COLUMN favicon_url VARCHAR(2048),
+    ADD COLUMN canonical_link VARCHAR(2048),
+    ADD COLUMN scraped_canonical_url VARCHAR(2048),
+    ADD COLUMN oembed_url VARCHAR(2048),
+    ADD COLUMN language VARCHAR(35),
+    ADD COLUMN keywords TEXT[],
+    ADD COLUMN published_at TIMESTAMPTZ,
+    ADD COLUMN modified_at TIMESTAMPTZ,
+    ADD COLUMN document_type VARCHAR(20),
+    ADD COLUMN extras JSONB,
+    ADD COLUMN thumbnail_key VARCHAR(255),
+    ADD COLUMN scrape_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    ADD COLUMN retry_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN next_attempt_at TIMESTAMPTZ,
+    ADD COLUMN last_error TEXT;
+
+UPDATE articles a SET
+    title = p.title, description = p.description, image_url = p.image_url, author = p.author, site_name = p.site_name,
+    favicon_url = p.favicon_url, canonical_link = p.canonical_link, scraped_canonical_url = p.scraped_canonical_url,
+    oembed_url = p.oembed_url, language = p.language, keywords = p.keywords, published_at = p.published_at,
+    modified_at = p.modified_at, document_type = p.document_type, extras = p.extras, thumbnail_key = p.thumbnail_key,
+    scrape_status = p.scrape_status, retry_count = p.retry_count, next_attempt_at = p.next_attempt_at, last_error = p.last_error
+FROM pages p
+WHERE p.id = a.page_id;
+
+CREATE INDEX idx_articles_status_next_attempt ON articles(scrape_status, next_attempt_at);
+CREATE INDEX articles_user_email_scraped_canonical_url_idx ON articles (user_email, scraped_canonical_url);
+
+-- 頁面的任務、內文與爬取紀錄交給每一篇收藏該頁面的文章
+INSERT INTO scrape_jobs (payload)
+SELECT a.id::text FROM articles a JOIN scrape_jobs j ON j.payload = a.page_id::text
+ON CONFLICT (payload) DO NOTHING;
+DELETE FROM scrape_jobs j WHERE EXISTS (SELECT 1 FROM pages p WHERE p.id::text = j.payload);
+
+CREATE TABLE article_contents (
+    article_id UUID PRIMARY KEY,
+    content_html TEXT NOT NULL DEFAULT '',
+    content_text TEXT NOT NULL DEFAULT '',
+    word_count INT NOT NULL DEFAULT 0,
+    reading_time_minutes INT NOT NULL DEFAULT 0,
+    extracted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_article
+        FOREIGN KEY(article_id)
+        REFERENCES articles(id)
+        ON DELETE CASCADE
+);
+
+INSERT INTO article_contents (article_id, content_html, content_text, word_count, reading_time_minutes, extracted_at)
+SELECT a.id, c.content_html, c.content_text, c.word_count, c.reading_time_minutes, c.extracted_at
+FROM page_contents c
+JOIN articles a ON a.page_id = c.page_id;
+
+DROP TABLE page_contents;
+
+ALTER TABLE scrape_attempts ADD COLUMN article_id UUID;
+INSERT INTO scrape_attempts (article_id, success, http_status, error_class, error_message, duration_ms, final_url, attempted_at)
+SELECT a.id, s.success, s.http_status, s.error_class, s.error_message, s.duration_ms, s.final_url, s.attempted_at
+FROM scrape_attempts s
+JOIN articles a ON a.page_id = s.page_id;
+DELETE FROM scrape_attempts WHERE article_id IS NULL;
+DROP INDEX IF EXISTS idx_scrape_attempts_page_id;
+ALTER TABLE scrape_attempts
+    DROP COLUMN page_id,
+    ALTER COLUMN article_id SET NOT NULL,
+    ADD CONSTRAINT fk_article FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_articles_page_id;
+ALTER TABLE articles DROP COLUMN page_id;
+DROP TABLE pages;
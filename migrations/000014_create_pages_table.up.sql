@@ -0,0 +1,118 @@
+-- pages 以正規化網址為 key，爬取結果由所有收藏同一網址的使用者共用；articles 只保留使用者與頁面的關聯
+CREATE TABLE pages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    canonical_url VARCHAR(2048) NOT NULL UNIQUE,
+    url VARCHAR(2048) NOT NULL, -- 實際爬取的網址，取第一位使用者提交的原始網址
+    title VARCHAR(255) DEFAULT '',
+    description TEXT DEFAULT '',
+    image_url VARCHAR(2048) DEFAULT '',
+    author TEXT,
+    site_name TEXT,
+    favicon_url VARCHAR(2048),
+    canonical_link VARCHAR(2048),
+    scraped_canonical_url VARCHAR(2048),
+    oembed_url VARCHAR(2048),
+    language VARCHAR(35),
+    keywords TEXT[],
+    published_at TIMESTAMPTZ,
+    modified_at TIMESTAMPTZ,
+    document_type VARCHAR(20),
+    extras JSONB,
+    thumbnail_key VARCHAR(255),
+    scrape_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    retry_count INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    last_error TEXT,
+    scraped_at TIMESTAMPTZ, -- 最近一次爬取成功的時間，超過 TTL 後重新爬取
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_pages_status_next_attempt ON pages(scrape_status, next_attempt_at);
+CREATE INDEX idx_pages_scraped_at ON pages(scraped_at) WHERE scrape_status = 'success';
+CREATE INDEX idx_pages_scraped_canonical_url ON pages(scraped_canonical_url);
+
+-- 既有文章依正規化網址合併為頁面 (舊資料沒有 canonical_url 時以原始網址為 key)，優先採用爬取成功且最新的那一筆
+INSERT INTO pages (
+    canonical_url, url, title, description, image_url, author, site_name, favicon_url, canonical_link, scraped_canonical_url,
+    oembed_url, language, keywords, published_at, modified_at, document_type, extras, thumbnail_key,
+    scrape_status, retry_count, next_attempt_at, last_error, scraped_at, created_at, updated_at
+)
+SELECT DISTINCT ON (COALESCE(canonical_url, url))
+    COALESCE(canonical_url, url), url, title, description, image_url, author, site_name, favicon_url, canonical_link, scraped_canonical_url,
+    oembed_url, language, keywords, published_at, modified_at, document_type, extras, thumbnail_key,
+    scrape_status, retry_count, next_attempt_at, last_error,
+    CASE WHEN scrape_status = 'success' THEN updated_at END, created_at, updated_at
+FROM articles
+ORDER BY COALESCE(canonical_url, url), scrape_status = 'success' DESC, updated_at DESC;
+
+ALTER TABLE articles ADD COLUMN page_id UUID;
+UPDATE articles a SET page_id = p.id FROM pages p WHERE p.canonical_url = COALESCE(a.canonical_url, a.url);
+ALTER TABLE articles
+    ALTER COLUMN page_id SET NOT NULL,
+    ADD CONSTRAINT fk_page FOREIGN KEY(page_id) REFERENCES pages(id);
+CREATE INDEX idx_articles_page_id ON articles(page_id);
+
+-- 爬取紀錄與內文改為屬於頁面
+ALTER TABLE scrape_attempts ADD COLUMN page_id UUID;
+UPDATE scrape_attempts s SET page_id = a.page_id FROM articles a WHERE a.id = s.article_id;
+ALTER TABLE scrape_attempts
+    DROP COLUMN article_id,
+    ALTER COLUMN page_id SET NOT NULL,
+    ADD CONSTRAINT fk_page FOREIGN KEY(page_id) REFERENCES pages(id) ON DELETE CASCADE;
+CREATE INDEX idx_scrape_attempts_page_id ON scrape_attempts(page_id, attempted_at DESC);
+
+CREATE TABLE page_contents (
+    page_id UUID PRIMARY KEY,
+    content_html TEXT NOT NULL DEFAULT '',
+    content_text TEXT NOT NULL DEFAULT '',
+    word_count INT NOT NULL DEFAULT 0,
+    reading_time_minutes INT NOT NULL DEFAULT 0,
+    extracted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT fk_page
+        FOREIGN KEY(page_id)
+        REFERENCES pages(id)
+        ON DELETE CASCADE
+);
+
+INSERT INTO page_contents (page_id, content_html, content_text, word_count, reading_time_minutes, extracted_at)
+SELECT DISTINCT ON (a.page_id) a.page_id, c.content_html, c.content_text, c.word_count, c.reading_time_minutes, c.extracted_at
+FROM article_contents c
+JOIN articles a ON a.id = c.article_id
+ORDER BY a.page_id, c.extracted_at DESC;
+
+DROP TABLE article_contents;
+
+-- 佇列中尚未處理的任務改為以頁面 ID 為 payload
+INSERT INTO scrape_jobs (payload, attempts, visible_at)
+SELECT DISTINCT ON (a.page_id) a.page_id::text, j.attempts, j.visible_at
+FROM scrape_jobs j
+JOIN articles a ON a.id::text = j.payload
+ORDER BY a.page_id, j.visible_at
+ON CONFLICT (payload) DO NOTHING;
+DELETE FROM scrape_jobs j WHERE NOT EXISTS (SELECT 1 FROM pages p WHERE p.id::text = j.payload);
+
+DROP INDEX IF EXISTS idx_articles_status_next_attempt;
+DROP INDEX IF EXISTS articles_user_email_scraped_canonical_url_idx;
+ALTER TABLE articles
+    DROP COLUMN title,
+    DROP COLUMN description,
+    DROP COLUMN image_url,
+    DROP COLUMN author,
+    DROP COLUMN site_name,
+    DROP COLUMN favicon_url,
+    DROP COLUMN canonical_link,
+    DROP COLUMN scraped_canonical_url,
+    DROP COLUMN oembed_url,
+    DROP COLUMN language,
+    DROP COLUMN keywords,
+    DROP COLUMN published_at,
+    DROP COLUMN modified_at,
+    DROP COLUMN document_type,
+    DROP COLUMN extras,
+    DROP COLUMN thumbnail_key,
+    DROP COLUMN scrape_status,
+    DROP COLUMN retry_count,
+    DROP COLUMN next_attempt_at,
+    DROP COLUMN last_error;
@@ -0,0 +1,4 @@
+ALTER TABLE articles
+    DROP COLUMN custom_title,
+    DROP COLUMN custom_description,
+    DROP COLUMN notes;
@@ -0,0 +1,5 @@
+-- 使用者自訂的標題、描述與筆記，優先於頁面爬取到的值；存在 articles 上，重新爬取不會覆蓋
+ALTER TABLE articles
+    ADD COLUMN custom_title VARCHAR(255),
+    ADD COLUMN custom_description TEXT,
+    ADD COLUMN notes TEXT;
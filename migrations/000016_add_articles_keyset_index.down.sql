@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_articles_user_email_created_at_id;
@@ -0,0 +1,2 @@
+-- 文章列表以 (created_at, id) 做 keyset 分頁
+CREATE INDEX idx_articles_user_email_created_at_id ON articles(user_email, created_at DESC, id DESC);
@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_ratings_search_vector;
+ALTER TABLE ratings DROP COLUMN search_vector;
+
+DROP INDEX IF EXISTS idx_articles_custom_description_trgm;
+DROP INDEX IF EXISTS idx_articles_custom_title_trgm;
+DROP INDEX IF EXISTS idx_articles_search_vector;
+ALTER TABLE articles DROP COLUMN search_vector;
+
+DROP INDEX IF EXISTS idx_pages_description_trgm;
+DROP INDEX IF EXISTS idx_pages_title_trgm;
+DROP INDEX IF EXISTS idx_pages_search_vector;
+ALTER TABLE pages DROP COLUMN search_vector;
+
+DROP FUNCTION IF EXISTS immutable_array_to_string(TEXT[], TEXT);
+-- pg_trgm 可能也被其他資料庫物件使用，不移除
@@ -0,0 +1,34 @@
+-- 全文搜尋：使用 simple 設定 (不做 stemming)，中英混合的內容才不會被英文規則切壞
+-- 中文沒有空白分詞，整段會被視為一個詞，因此另以 pg_trgm 的 ILIKE 比對作為 fallback
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- array_to_string 不是 IMMUTABLE，不能直接用在 generated column
+CREATE FUNCTION immutable_array_to_string(arr TEXT[], sep TEXT) RETURNS TEXT
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE
+    AS $$ SELECT array_to_string(arr, sep) $$;
+
+ALTER TABLE pages ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(site_name, '') || ' ' || coalesce(author, '')), 'C') ||
+    setweight(to_tsvector('simple', coalesce(url, '')), 'D')
+) STORED;
+CREATE INDEX idx_pages_search_vector ON pages USING GIN (search_vector);
+CREATE INDEX idx_pages_title_trgm ON pages USING GIN (title gin_trgm_ops);
+CREATE INDEX idx_pages_description_trgm ON pages USING GIN (description gin_trgm_ops);
+
+-- 使用者自訂的欄位與提交的網址
+ALTER TABLE articles ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(custom_title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(custom_description, '') || ' ' || coalesce(notes, '')), 'B') ||
+    setweight(to_tsvector('simple', url), 'D')
+) STORED;
+CREATE INDEX idx_articles_search_vector ON articles USING GIN (search_vector);
+CREATE INDEX idx_articles_custom_title_trgm ON articles USING GIN (custom_title gin_trgm_ops);
+CREATE INDEX idx_articles_custom_description_trgm ON articles USING GIN (custom_description gin_trgm_ops);
+
+-- 使用者評分時加上的標籤
+ALTER TABLE ratings ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(immutable_array_to_string(tags, ' '), '')), 'A')
+) STORED;
+CREATE INDEX idx_ratings_search_vector ON ratings USING GIN (search_vector);
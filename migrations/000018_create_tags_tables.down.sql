@@ -0,0 +1,22 @@
+ALTER TABLE ratings ADD COLUMN tags TEXT[];
+
+-- 只有評分過的文章能保留標籤
+UPDATE ratings r SET tags = ARRAY(
+    SELECT t.name
+    FROM article_tags atg
+    JOIN tags t ON t.id = atg.tag_id
+    WHERE atg.article_id = r.article_id
+    ORDER BY t.name
+);
+
+CREATE FUNCTION immutable_array_to_string(arr TEXT[], sep TEXT) RETURNS TEXT
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE
+    AS $$ SELECT array_to_string(arr, sep) $$;
+
+ALTER TABLE ratings ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(immutable_array_to_string(tags, ' '), '')), 'A')
+) STORED;
+CREATE INDEX idx_ratings_search_vector ON ratings USING GIN (search_vector);
+
+DROP TABLE article_tags;
+DROP TABLE tags;
@@ -0,0 +1,54 @@
+-- 標籤獨立於評分，未評分的文章也能加上標籤
+-- 名稱已正規化 (去除前後空白、連續空白合併為一個、轉小寫)，每位使用者的標籤名稱唯一
+CREATE TABLE tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_email VARCHAR(255) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    UNIQUE (user_email, name),
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+);
+
+CREATE TABLE article_tags (
+    article_id UUID NOT NULL,
+    tag_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (article_id, tag_id),
+
+    CONSTRAINT fk_article
+        FOREIGN KEY(article_id)
+        REFERENCES articles(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_tag
+        FOREIGN KEY(tag_id)
+        REFERENCES tags(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_article_tags_tag_id ON article_tags(tag_id);
+
+-- 將評分中的標籤搬到新的資料表，正規化後相同的標籤合併為一個
+WITH rating_tags AS (
+    SELECT DISTINCT r.user_email, r.article_id,
+        btrim(left(lower(regexp_replace(btrim(t.tag), '\s+', ' ', 'g')), 64)) AS name
+    FROM ratings r, unnest(r.tags) AS t(tag)
+), new_tags AS (
+    INSERT INTO tags (user_email, name)
+    SELECT DISTINCT user_email, name FROM rating_tags WHERE name <> ''
+    RETURNING id, user_email, name
+)
+INSERT INTO article_tags (article_id, tag_id)
+SELECT DISTINCT rt.article_id, nt.id
+FROM rating_tags rt
+JOIN new_tags nt ON nt.user_email = rt.user_email AND nt.name = rt.name;
+
+DROP INDEX IF EXISTS idx_ratings_search_vector;
+ALTER TABLE ratings DROP COLUMN search_vector;
+ALTER TABLE ratings DROP COLUMN tags;
+DROP FUNCTION IF EXISTS immutable_array_to_string(TEXT[], TEXT);
@@ -0,0 +1,2 @@
+DROP TABLE collection_articles;
+DROP TABLE collections;
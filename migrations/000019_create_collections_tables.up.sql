@@ -0,0 +1,51 @@
+-- 收藏夾 (資料夾)，一篇文章可以放在多個收藏夾中
+-- 每位使用者有一個預設的 Inbox (is_default)，新收藏的文章會放進 Inbox，Inbox 不能刪除
+CREATE TABLE collections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_email VARCHAR(255) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    UNIQUE (user_email, name),
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_email)
+        REFERENCES users(email)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_collections_default ON collections(user_email) WHERE is_default;
+
+-- position 是文章在收藏夾中的順序 (由小到大)，移除文章時不重新編號，只保證相對順序
+CREATE TABLE collection_articles (
+    collection_id UUID NOT NULL,
+    article_id UUID NOT NULL,
+    position INT NOT NULL,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (collection_id, article_id),
+
+    CONSTRAINT fk_collection
+        FOREIGN KEY(collection_id)
+        REFERENCES collections(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_article
+        FOREIGN KEY(article_id)
+        REFERENCES articles(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_collection_articles_article_id ON collection_articles(article_id);
+CREATE INDEX idx_collection_articles_position ON collection_articles(collection_id, position, article_id);
+
+-- 為既有使用者建立 Inbox，並依收藏時間放入既有的文章
+INSERT INTO collections (user_email, name, is_default)
+SELECT email, 'Inbox', true FROM users;
+
+INSERT INTO collection_articles (collection_id, article_id, position, added_at)
+SELECT c.id, a.id, row_number() OVER (PARTITION BY a.user_email ORDER BY a.created_at, a.id), a.created_at
+FROM articles a
+JOIN collections c ON c.user_email = a.user_email AND c.is_default;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_articles_unarchived;
+
+ALTER TABLE articles DROP COLUMN favorited;
+ALTER TABLE articles DROP COLUMN archived_at;
+ALTER TABLE articles DROP COLUMN read_at;
@@ -0,0 +1,7 @@
+-- 閱讀狀態屬於使用者自己的收藏，存在 articles 上
+ALTER TABLE articles ADD COLUMN read_at TIMESTAMPTZ;
+ALTER TABLE articles ADD COLUMN archived_at TIMESTAMPTZ;
+ALTER TABLE articles ADD COLUMN favorited BOOLEAN NOT NULL DEFAULT false;
+
+-- 文章列表預設只列出未封存的文章
+CREATE INDEX idx_articles_unarchived ON articles(user_email, created_at DESC, id DESC) WHERE archived_at IS NULL;